@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"veko-grid/config"
+	"veko-grid/core"
+	"veko-grid/utils"
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "📜 Monitoring expiry sertifikat TLS",
+	Long: `📜 Certs memindai setiap port TLS terbuka (implicit TLS dan STARTTLS) di semua
+target, lalu menampilkan sertifikat leaf diurutkan berdasarkan sisa hari dan
+dikelompokkan per issuer.
+
+Baris pertama output berisi ringkasan status gaya plugin monitoring dan exit
+code mengikuti konvensi Nagios/Icinga, sehingga cocok untuk cron:
+  0 = OK, 1 = WARNING (≤ --warn-days), 2 = CRITICAL (≤ --crit-days atau expired),
+  3 = UNKNOWN (target tidak resolve, tidak ada port TLS, atau handshake gagal)
+
+Setiap alamat A dan AAAA target dipindai. Sertifikat leaf dikumpulkan dengan
+satu handshake TLS permissive (TLS 1.0-1.3, semua cipher suite) per port, tanpa
+uji sesi, HSTS, probe versi maupun validasi chain.
+
+Contoh penggunaan:
+  veko-grid certs --input targets.txt --warn-days 30 --crit-days 7
+  veko-grid certs --input targets.txt --ports 4443,9443 --json`,
+	Annotations: map[string]string{"banner": "off"},
+	RunE:        runCerts,
+}
+
+var (
+	certsInputFile string
+	certsOutput    string
+	certsWarnDays  int
+	certsCritDays  int
+	certsPorts     []int
+	certsTimeout   int
+	certsThreads   int
+	certsCABundle  string
+	certsResolvers []string
+	certsJSON      bool
+	certsSilent    bool
+	certsDebug     bool
+)
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+
+	certsCmd.Flags().StringVarP(&certsInputFile, "input", "i", "", "File berisi daftar target (domain/IP)")
+	certsCmd.Flags().StringVarP(&certsOutput, "output", "o", "", "File output JSON (opsional)")
+	certsCmd.Flags().IntVar(&certsWarnDays, "warn-days", utils.DefaultExpiryWarnDays, "Ambang WARNING: sisa hari sebelum expiry")
+	certsCmd.Flags().IntVar(&certsCritDays, "crit-days", utils.DefaultExpiryCritDays, "Ambang CRITICAL: sisa hari sebelum expiry")
+	certsCmd.Flags().IntSliceVar(&certsPorts, "ports", nil, "Port tambahan selain port umum (misal 4443,9443)")
+	certsCmd.Flags().IntVar(&certsTimeout, "timeout", 5, "Timeout koneksi (detik)")
+	certsCmd.Flags().IntVar(&certsThreads, "threads", 10, "Maksimum target concurrent")
+	certsCmd.Flags().StringVar(&certsCABundle, "ca-bundle", "", "CA bundle PEM untuk validasi certificate chain (default: system roots)")
+	certsCmd.Flags().StringSliceVar(&certsResolvers, "resolvers", nil, "Daftar DNS resolver (default: 8.8.8.8,1.1.1.1,9.9.9.9,208.67.222.222)")
+	certsCmd.Flags().BoolVar(&certsJSON, "json", false, "Output laporan dalam format JSON ke stdout")
+	certsCmd.Flags().BoolVar(&certsSilent, "silent", false, "Hanya cetak baris ringkasan status")
+	certsCmd.Flags().BoolVar(&certsDebug, "debug", false, "Enable debug logging")
+
+	certsCmd.MarkFlagRequired("input")
+}
+
+func runCerts(cmd *cobra.Command, args []string) error {
+	if certsCritDays > certsWarnDays {
+		return fmt.Errorf("❌ --crit-days (%d) tidak boleh lebih besar dari --warn-days (%d)", certsCritDays, certsWarnDays)
+	}
+
+	// Logger silent (kecuali --debug) agar stdout bersih untuk cron/monitoring
+	logger := utils.NewLogger(certsDebug, !certsDebug)
+
+	targets, err := readTargetsFromFile(certsInputFile)
+	if err != nil {
+		return fmt.Errorf("❌ Error membaca file targets: %v", err)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("❌ Tidak ada target yang valid ditemukan")
+	}
+
+	cfg := &config.Config{
+		Timeout:    certsTimeout,
+		DNSMode:    "default",
+		Silent:     true,
+		MaxThreads: certsThreads,
+		Resolvers:  certsResolvers,
+		EDNSBuffer: utils.DefaultEDNSBufferSize,
+		CABundle:   certsCABundle,
+		Ports:      certsPorts,
+	}
+
+	scanner, err := core.NewScanner(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("❌ Error inisialisasi scanner: %v", err)
+	}
+
+	report := utils.BuildExpiryReport(scanner.ScanCertificates(targets), certsWarnDays, certsCritDays)
+
+	if certsOutput != "" {
+		outputHandler := utils.NewOutputHandler(&config.Config{OutputFile: certsOutput, Silent: true}, logger)
+		if err := outputHandler.SaveResults(report); err != nil {
+			return fmt.Errorf("❌ Error menyimpan hasil: %v", err)
+		}
+	}
+
+	if certsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		displayExpiryReport(report)
+	}
+
+	if code := report.ExitCode(); code != 0 {
+		os.Exit(code)
+	}
+	return nil
+}
+
+// displayExpiryReport menampilkan ringkasan status, daftar sertifikat dan grup issuer
+func displayExpiryReport(report *utils.ExpiryReport) {
+	fmt.Printf("CERTS %s - %d critical, %d warning, %d unknown, %d ok (warn=%dd crit=%dd)\n",
+		report.Status, report.Critical, report.Warning, report.Unknown, report.OK, report.WarnDays, report.CritDays)
+
+	if certsSilent || len(report.Certificates) == 0 {
+		return
+	}
+
+	fmt.Println()
+	for _, cert := range report.Certificates {
+		if cert.Status == utils.ExpiryUnknown {
+			fmt.Printf("%-8s %6s  %-10s  %-22s %-8s %s: %s\n", cert.Status, "-", "-",
+				cert.Address, cert.Protocol, cert.Target, cert.Error)
+			continue
+		}
+		fmt.Printf("%-8s %5dd  %s  %-22s %-8s %s\n", cert.Status, cert.DaysUntilExpiry,
+			cert.NotAfter.Format("2006-01-02"), cert.Address, cert.Protocol, cert.Subject)
+	}
+
+	fmt.Println()
+	for _, group := range report.Issuers {
+		fmt.Printf("📜 %s (%d)\n", group.Issuer, len(group.Certificates))
+		for _, cert := range group.Certificates {
+			fmt.Printf("    %-8s %5dd  %-22s %s\n", cert.Status, cert.DaysUntilExpiry, cert.Address, cert.Target)
+		}
+	}
+}
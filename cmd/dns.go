@@ -0,0 +1,793 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"veko-grid/config"
+	"veko-grid/proxy"
+	"veko-grid/utils"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "📡 Eksplorasi DNS untuk domain",
+	Long: `📡 DNS command berisi tool eksplorasi DNS yang berdiri sendiri
+di luar grid scanning.
+
+Subcommand:
+• enum - Subdomain brute-force dengan deteksi wildcard
+• axfr - Cek zone transfer (AXFR/IXFR) ke setiap nameserver
+• dnssec - Validasi DNSSEC chain of trust
+• email - Postur keamanan email (SPF, DMARC, DKIM, MTA-STS, TLS-RPT, BIMI)
+• records - Query record types pilihan (SOA, CAA, SRV, HTTPS/SVCB, DS, TLSA, ...)
+• compare - Bandingkan jawaban antar resolver (split-horizon/tampering)
+• ptr-sweep - Reverse DNS sweep CIDR dengan pengecekan FCrDNS
+• ecs - Probing EDNS Client Subnet untuk jawaban berbasis lokasi
+• delegation - Audit delegasi NS (lame, parent/child mismatch, serial, ASN, glue)
+• walk - Penilaian exposure zone walking NSEC/NSEC3 (RFC 9276)`,
+}
+
+var dnsEnumCmd = &cobra.Command{
+	Use:   "enum",
+	Short: "🔎 Subdomain brute-force enumeration",
+	Long: `🔎 Enum melakukan brute-force subdomain dari wordlist secara concurrent.
+
+Sebelum enumerasi, beberapa label random di-resolve untuk mendeteksi
+wildcard DNS. Kandidat yang jawabannya identik dengan wildcard dibuang.
+
+Contoh penggunaan:
+  veko-grid dns enum --domain example.com --wordlist words.txt --depth 2`,
+	RunE: runDNSEnum,
+}
+
+var dnsAXFRCmd = &cobra.Command{
+	Use:   "axfr",
+	Short: "🚨 Cek exposure zone transfer (AXFR/IXFR)",
+	Long: `🚨 AXFR mencoba zone transfer AXFR dan IXFR lewat TCP ke setiap
+nameserver domain dan melaporkan server mana yang mengizinkan.
+
+Contoh penggunaan:
+  veko-grid dns axfr --domain example.com`,
+	RunE: runDNSAXFR,
+}
+
+var dnsSECCmd = &cobra.Command{
+	Use:   "dnssec",
+	Short: "🔐 Validasi DNSSEC chain of trust",
+	Long: `🔐 DNSSEC memvalidasi chain of trust dari trust anchor (default root KSK)
+sampai domain: DS, DNSKEY dan RRSIG di setiap zone cut. Delegasi tanpa DS dan
+jawaban NODATA/NXDOMAIN harus dibuktikan NSEC/NSEC3 yang valid, jika tidak bogus.
+
+Status: secure, insecure, bogus, atau indeterminate beserta link yang gagal.
+
+Contoh penggunaan:
+  veko-grid dns dnssec --domain example.com --trust-anchor root-anchors.txt`,
+	RunE: runDNSSEC,
+}
+
+var dnsEmailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "📧 Analisis postur keamanan email",
+	Long: `📧 Email mengevaluasi SPF (dengan ekspansi include dan batas 10 lookup),
+DMARC, DKIM selector probing, MTA-STS, TLS-RPT dan BIMI untuk domain.
+
+Contoh penggunaan:
+  veko-grid dns email --domain example.com --selectors google,selector1`,
+	RunE: runDNSEmail,
+}
+
+var dnsRecordsCmd = &cobra.Command{
+	Use:   "records",
+	Short: "📑 Query record types pilihan untuk domain",
+	Long: `📑 Records melakukan query record types yang dipilih untuk domain.
+
+Record types: A, AAAA, CNAME, MX, NS, TXT, SOA, CAA, SRV, HTTPS, SVCB, DS, TLSA
+(gunakan "all" untuk semua). SRV di-query untuk service umum (_sip, _ldap,
+_xmpp, ...) dan TLSA untuk _443._tcp, _25._tcp, _465._tcp dan _587._tcp.
+
+Contoh penggunaan:
+  veko-grid dns records --domain example.com --types SOA,CAA,HTTPS,TLSA`,
+	RunE: runDNSRecords,
+}
+
+var dnsCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "⚖️ Bandingkan jawaban DNS antar resolver",
+	Long: `⚖️ Compare meng-query nama yang sama ke setiap resolver yang dikonfigurasi
+lalu membandingkan answer set, TTL dan rcode. Inkonsistensi seperti A set
+berbeda, NXDOMAIN yang tidak wajar, atau IP privat yang disisipkan dilaporkan
+beserta bukti per resolver.
+
+Contoh penggunaan:
+  veko-grid dns compare --domain example.com --type A --resolvers 8.8.8.8,1.1.1.1,192.168.1.1`,
+	RunE: runDNSCompare,
+}
+
+var dnsPTRSweepCmd = &cobra.Command{
+	Use:   "ptr-sweep",
+	Short: "🔁 Reverse DNS sweep untuk range IP",
+	Long: `🔁 PTR sweep melakukan PTR lookup concurrent untuk setiap IP di CIDR
+yang diberikan dan menghasilkan inventaris IP → hostname.
+
+Setiap hostname dicek forward-confirmed reverse DNS (FCrDNS): hostname
+di-resolve kembali dan harus mengarah ke IP asal. Lookup dijalankan oleh
+--threads worker; gunakan --rate untuk membatasi query per detik. Satu CIDR
+maksimal /16 (IPv4) atau /112 (IPv6).
+
+Contoh penggunaan:
+  veko-grid dns ptr-sweep --cidr 192.0.2.0/24,198.51.100.0/28 --rate 50`,
+	RunE: runDNSPTRSweep,
+}
+
+var dnsECSCmd = &cobra.Command{
+	Use:   "ecs",
+	Short: "🗺️ Probing EDNS Client Subnet (jawaban per region)",
+	Long: `🗺️ ECS mengirim query dengan opsi EDNS Client Subnet untuk beberapa
+client subnet sintetis ke setiap resolver. Jawaban per subnet dan scope prefix
+yang dikembalikan dikumpulkan untuk memetakan edge address CDN per region.
+
+Resolver yang tidak mengembalikan scope prefix dianggap tidak mendukung ECS
+(misal 1.1.1.1). Subnet berformat region=cidr atau cidr.
+
+Contoh penggunaan:
+  veko-grid dns ecs --domain www.example.com --resolvers 8.8.8.8,208.67.222.222
+  veko-grid dns ecs --domain www.example.com --subnets us=24.0.0.0/24,jp=126.0.0.0/24`,
+	RunE: runDNSECS,
+}
+
+var dnsDelegationCmd = &cobra.Command{
+	Use:   "delegation",
+	Short: "🏛️ Audit kesehatan delegasi NS",
+	Long: `🏛️ Delegation membandingkan NS set di parent zone dengan NS set yang
+disajikan server authoritative child, meng-query SOA langsung ke setiap
+server, lalu melaporkan lame server, serial tidak sinkron, nameserver dalam
+satu ASN dan glue record yang hilang.
+
+Contoh penggunaan:
+  veko-grid dns delegation --domain example.com`,
+	RunE: runDNSDelegation,
+}
+
+var dnsWalkCmd = &cobra.Command{
+	Use:   "walk",
+	Short: "🚶 Penilaian exposure NSEC/NSEC3 zone walking",
+	Long: `🚶 Walk menilai apakah denial of existence DNSSEC membocorkan isi zone.
+
+NSEC: rantai NSEC diikuti dari apex sampai kembali ke apex atau --limit,
+semua nama yang ditemukan dilaporkan.
+NSEC3: hash dikumpulkan dari jawaban NXDOMAIN dan parameter (iterasi, salt,
+opt-out) dinilai terhadap rekomendasi RFC 9276.
+
+Contoh penggunaan:
+  veko-grid dns walk --domain example.com --limit 500`,
+	RunE: runDNSWalk,
+}
+
+var (
+	dnsOutputFile string
+	dnsUseDoH     bool
+	dnsThreads    int
+	dnsSilent     bool
+	dnsDebug      bool
+	dnsResolvers  []string
+	dnsRate       int
+	dnsEDNSBuffer int
+	dnsProxyAddr  string
+	dnsUseTor     bool
+	dnsStrict     bool
+
+	enumDomain   string
+	enumWordlist string
+	enumMaxDepth int
+
+	axfrDomain string
+
+	dnssecDomain      string
+	dnssecTrustAnchor string
+
+	emailDomain    string
+	emailSelectors []string
+
+	recordsDomain string
+	recordsTypes  []string
+
+	compareDomain string
+	compareTypes  []string
+
+	ptrCIDRs []string
+
+	ecsDomain  string
+	ecsType    string
+	ecsSubnets []string
+
+	delegationDomain string
+
+	walkDomain string
+	walkLimit  int
+)
+
+func init() {
+	rootCmd.AddCommand(dnsCmd)
+	dnsCmd.AddCommand(dnsEnumCmd)
+	dnsCmd.AddCommand(dnsAXFRCmd)
+	dnsCmd.AddCommand(dnsSECCmd)
+	dnsCmd.AddCommand(dnsEmailCmd)
+	dnsCmd.AddCommand(dnsRecordsCmd)
+	dnsCmd.AddCommand(dnsCompareCmd)
+	dnsCmd.AddCommand(dnsPTRSweepCmd)
+	dnsCmd.AddCommand(dnsECSCmd)
+	dnsCmd.AddCommand(dnsDelegationCmd)
+	dnsCmd.AddCommand(dnsWalkCmd)
+
+	// Flags bersama untuk semua subcommand DNS
+	dnsCmd.PersistentFlags().StringVarP(&dnsOutputFile, "output", "o", "", "File output JSON (opsional)")
+	dnsCmd.PersistentFlags().BoolVar(&dnsUseDoH, "doh", false, "Gunakan DNS over HTTPS")
+	dnsCmd.PersistentFlags().IntVar(&dnsThreads, "threads", 20, "Maksimum query concurrent")
+	dnsCmd.PersistentFlags().BoolVar(&dnsSilent, "silent", false, "Mode silent (minimal output)")
+	dnsCmd.PersistentFlags().BoolVar(&dnsDebug, "debug", false, "Enable debug logging")
+	dnsCmd.PersistentFlags().StringSliceVar(&dnsResolvers, "resolvers", nil, "Daftar DNS resolver (default: 8.8.8.8,1.1.1.1,9.9.9.9,208.67.222.222)")
+	dnsCmd.PersistentFlags().IntVar(&dnsRate, "rate", 0, "Maksimum query DNS per detik (0 = tanpa batas)")
+	dnsCmd.PersistentFlags().StringVarP(&dnsProxyAddr, "proxy", "p", "", "Query DNS lewat proxy (socks5://127.0.0.1:9050)")
+	dnsCmd.PersistentFlags().BoolVar(&dnsUseTor, "tor", false, "Query DNS lewat TOR (DNS over TCP)")
+	dnsCmd.PersistentFlags().BoolVar(&dnsStrict, "strict-dns", false, "Batalkan query jika proxy/TOR gagal (tanpa fallback ke direct DNS)")
+	dnsCmd.PersistentFlags().IntVar(&dnsEDNSBuffer, "edns-buffer", utils.DefaultEDNSBufferSize, "Ukuran buffer UDP EDNS0 (byte), fallback TCP jika truncated")
+
+	// Enum flags
+	dnsEnumCmd.Flags().StringVarP(&enumDomain, "domain", "d", "", "Domain yang akan di-enumerasi")
+	dnsEnumCmd.Flags().StringVarP(&enumWordlist, "wordlist", "w", "", "File wordlist subdomain")
+	dnsEnumCmd.Flags().IntVar(&enumMaxDepth, "depth", 1, "Batas kedalaman enumerasi rekursif")
+	dnsEnumCmd.MarkFlagRequired("domain")
+	dnsEnumCmd.MarkFlagRequired("wordlist")
+
+	// AXFR flags
+	dnsAXFRCmd.Flags().StringVarP(&axfrDomain, "domain", "d", "", "Domain yang akan dicek")
+	dnsAXFRCmd.MarkFlagRequired("domain")
+
+	// DNSSEC flags
+	dnsSECCmd.Flags().StringVarP(&dnssecDomain, "domain", "d", "", "Domain yang akan divalidasi")
+	dnsSECCmd.Flags().StringVar(&dnssecTrustAnchor, "trust-anchor", "", "File trust anchor DS/DNSKEY (default: root KSK IANA)")
+	dnsSECCmd.MarkFlagRequired("domain")
+
+	// Email flags
+	dnsEmailCmd.Flags().StringVarP(&emailDomain, "domain", "d", "", "Domain yang akan dianalisis")
+	dnsEmailCmd.Flags().StringSliceVar(&emailSelectors, "selectors", nil, "Daftar DKIM selector (default: selector umum)")
+	dnsEmailCmd.MarkFlagRequired("domain")
+
+	// Records flags
+	dnsRecordsCmd.Flags().StringVarP(&recordsDomain, "domain", "d", "", "Domain yang akan di-query")
+	dnsRecordsCmd.Flags().StringSliceVarP(&recordsTypes, "types", "t", []string{"all"}, "Record types yang di-query")
+	dnsRecordsCmd.MarkFlagRequired("domain")
+
+	// Compare flags
+	dnsCompareCmd.Flags().StringVarP(&compareDomain, "domain", "d", "", "Nama yang akan dibandingkan")
+	dnsCompareCmd.Flags().StringSliceVarP(&compareTypes, "type", "t", []string{"A", "AAAA"}, "Record types yang dibandingkan")
+	dnsCompareCmd.MarkFlagRequired("domain")
+
+	// PTR sweep flags
+	dnsPTRSweepCmd.Flags().StringSliceVar(&ptrCIDRs, "cidr", nil, "Daftar CIDR atau IP yang akan di-sweep")
+	dnsPTRSweepCmd.MarkFlagRequired("cidr")
+
+	// ECS flags
+	dnsECSCmd.Flags().StringVarP(&ecsDomain, "domain", "d", "", "Nama yang akan di-probe")
+	dnsECSCmd.Flags().StringVarP(&ecsType, "type", "t", "A", "Record type yang di-query")
+	dnsECSCmd.Flags().StringSliceVar(&ecsSubnets, "subnets", nil, "Client subnet region=cidr (default: subnet sintetis per region)")
+	dnsECSCmd.MarkFlagRequired("domain")
+
+	// Delegation flags
+	dnsDelegationCmd.Flags().StringVarP(&delegationDomain, "domain", "d", "", "Zone yang akan diaudit")
+	dnsDelegationCmd.MarkFlagRequired("domain")
+
+	// Walk flags
+	dnsWalkCmd.Flags().StringVarP(&walkDomain, "domain", "d", "", "Zone DNSSEC yang akan dinilai")
+	dnsWalkCmd.Flags().IntVar(&walkLimit, "limit", utils.DefaultZoneWalkLimit, "Maksimum nama NSEC atau hash NSEC3 yang dikumpulkan")
+	dnsWalkCmd.MarkFlagRequired("domain")
+}
+
+func runDNSEnum(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	words, err := utils.LoadWordlist(enumWordlist)
+	if err != nil {
+		return fmt.Errorf("❌ Error membaca wordlist: %v", err)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("❌ Wordlist kosong: %s", enumWordlist)
+	}
+
+	logger.Info(fmt.Sprintf("📖 Enumerasi %s dengan %d kata (depth %d)", enumDomain, len(words), enumMaxDepth))
+
+	// Enumerasi per level sampai batas depth
+	var results []*utils.EnumResult
+	queue := []string{enumDomain}
+
+	for depth := 0; depth < enumMaxDepth && len(queue) > 0; depth++ {
+		var next []string
+
+		for _, domain := range queue {
+			result := resolver.EnumerateSubdomains(domain, words, dnsThreads)
+			for i := range result.Hits {
+				result.Hits[i].Depth = depth + 1
+				next = append(next, result.Hits[i].Name)
+			}
+			results = append(results, result)
+
+			if !dnsSilent {
+				displayEnumResult(result)
+			}
+		}
+
+		queue = next
+	}
+
+	return saveDNSResults(logger, results)
+}
+
+func runDNSAXFR(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	nameservers, err := resolver.LookupNS(axfrDomain)
+	if err != nil || len(nameservers) == 0 {
+		return fmt.Errorf("❌ NS records tidak ditemukan untuk %s: %v", axfrDomain, err)
+	}
+
+	result := resolver.CheckZoneTransfer(axfrDomain, nameservers)
+
+	if !dnsSilent {
+		displayZoneTransferResult(result)
+	}
+
+	return saveDNSResults(logger, result)
+}
+
+func runDNSSEC(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	if dnssecTrustAnchor != "" {
+		if err := resolver.LoadTrustAnchor(dnssecTrustAnchor); err != nil {
+			return fmt.Errorf("❌ Error membaca trust anchor: %v", err)
+		}
+	}
+
+	result := resolver.ValidateDNSSEC(dnssecDomain)
+
+	if !dnsSilent {
+		displayDNSSECResult(result)
+	}
+
+	return saveDNSResults(logger, result)
+}
+
+func runDNSEmail(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	result := resolver.AnalyzeEmailSecurity(emailDomain, nil, emailSelectors)
+
+	if !dnsSilent {
+		displayEmailSecurity(result)
+	}
+
+	return saveDNSResults(logger, result)
+}
+
+func runDNSRecords(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	if err := resolver.SetRecordTypes(recordsTypes); err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	records, err := resolver.ResolveAll(recordsDomain)
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	if !dnsSilent {
+		fmt.Printf("  🌐 Domain: %s\n", recordsDomain)
+		for _, recordType := range utils.SupportedRecordTypes {
+			for _, value := range records[recordType] {
+				fmt.Printf("    %-6s %s\n", recordType, value)
+			}
+		}
+		fmt.Println()
+	}
+
+	return saveDNSResults(logger, records)
+}
+
+func runDNSCompare(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	var results []*utils.ResolverComparison
+	for _, recordType := range compareTypes {
+		comparison, err := resolver.CompareResolvers(compareDomain, recordType)
+		if err != nil {
+			return fmt.Errorf("❌ %v", err)
+		}
+		results = append(results, comparison)
+
+		if !dnsSilent {
+			displayResolverComparison(comparison)
+		}
+	}
+
+	return saveDNSResults(logger, results)
+}
+
+func runDNSPTRSweep(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	result, err := resolver.SweepPTR(ptrCIDRs, dnsThreads)
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	if !dnsSilent {
+		displayPTRSweepResult(result)
+	}
+
+	return saveDNSResults(logger, result)
+}
+
+func runDNSECS(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	result, err := resolver.ProbeECS(ecsDomain, ecsType, ecsSubnets)
+	if err != nil {
+		return fmt.Errorf("❌ %v", err)
+	}
+
+	if !dnsSilent {
+		displayECSResult(result)
+	}
+
+	return saveDNSResults(logger, result)
+}
+
+func runDNSDelegation(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	result := resolver.AuditDelegation(delegationDomain, nil)
+
+	if !dnsSilent {
+		displayDelegationResult(result)
+	}
+
+	return saveDNSResults(logger, result)
+}
+
+func runDNSWalk(cmd *cobra.Command, args []string) error {
+	logger, resolver, err := newDNSTools()
+	if err != nil {
+		return err
+	}
+
+	result := resolver.WalkZone(walkDomain, walkLimit)
+
+	if !dnsSilent {
+		displayZoneWalkResult(result)
+	}
+
+	return saveDNSResults(logger, result)
+}
+
+// newDNSTools membuat logger dan resolver untuk subcommand DNS
+func newDNSTools() (*utils.Logger, *utils.DNSResolver, error) {
+	logger := utils.NewLogger(dnsDebug, dnsSilent)
+
+	resolver, err := utils.NewDNSResolver(dnsUseDoH, logger)
+	if err != nil {
+		return nil, nil, fmt.Errorf("❌ Error inisialisasi DNS resolver: %v", err)
+	}
+	resolver.SetServers(dnsResolvers)
+	resolver.SetRateLimit(dnsRate)
+	resolver.SetEDNSBufferSize(dnsEDNSBuffer)
+
+	// DNS lewat proxy/TOR agar domain tidak bocor dari IP asli
+	if dnsUseTor || dnsProxyAddr != "" {
+		proxyMgr, err := proxy.NewManager(dnsProxyAddr, dnsUseTor, logger)
+		if err != nil {
+			return nil, nil, fmt.Errorf("❌ Error inisialisasi proxy: %v", err)
+		}
+
+		dialer, err := proxyMgr.GetDNSDialer()
+		if err != nil || dialer == nil {
+			return nil, nil, fmt.Errorf("❌ Error inisialisasi DNS proxy dialer: %v", err)
+		}
+		resolver.SetProxyDialer(dialer, dnsStrict)
+	} else if dnsStrict {
+		return nil, nil, fmt.Errorf("❌ --strict-dns membutuhkan --tor atau --proxy")
+	}
+
+	return logger, resolver, nil
+}
+
+// saveDNSResults menyimpan hasil subcommand DNS jika --output diisi
+func saveDNSResults(logger *utils.Logger, results interface{}) error {
+	if dnsOutputFile == "" {
+		return nil
+	}
+
+	cfg := &config.Config{
+		OutputFile: dnsOutputFile,
+		DNSMode:    "default",
+		MaxThreads: dnsThreads,
+		Silent:     dnsSilent,
+	}
+	if dnsUseDoH {
+		cfg.DNSMode = "doh"
+	}
+
+	outputHandler := utils.NewOutputHandler(cfg, logger)
+	if err := outputHandler.SaveResults(results); err != nil {
+		return fmt.Errorf("❌ Error menyimpan hasil: %v", err)
+	}
+
+	return nil
+}
+
+// displayEnumResult menampilkan hasil enumerasi ke terminal
+func displayEnumResult(result *utils.EnumResult) {
+	fmt.Printf("  🌐 Domain: %s (%d kandidat, %s)\n", result.Domain, result.Tested, result.Duration)
+
+	if result.Wildcard != nil {
+		fmt.Printf("    ⚠️  Wildcard: %s (%d hasil difilter)\n",
+			strings.Join(append(append([]string{}, result.Wildcard.IPs...), result.Wildcard.CNAMEs...), ", "), result.Filtered)
+	}
+
+	for _, hit := range result.Hits {
+		values := append(append([]string{}, hit.IPs...), hit.CNAMEs...)
+		fmt.Printf("    ✅ %s → %s\n", hit.Name, strings.Join(values, ", "))
+	}
+
+	fmt.Println()
+}
+
+// displayZoneTransferResult menampilkan hasil zone transfer ke terminal
+func displayZoneTransferResult(result *utils.ZoneTransferResult) {
+	fmt.Printf("  🌐 Domain: %s\n", result.Domain)
+
+	for _, attempt := range result.Attempts {
+		if attempt.Allowed {
+			fmt.Printf("    🚨 %s %s (%s): ALLOWED, %d records\n", attempt.Type, attempt.Nameserver, attempt.Address, attempt.Records)
+		} else {
+			fmt.Printf("    🔒 %s %s (%s): refused (%s)\n", attempt.Type, attempt.Nameserver, attempt.Address, attempt.Error)
+		}
+	}
+
+	if len(result.Hosts) > 0 {
+		fmt.Printf("    📋 Hosts dari zone: %s\n", strings.Join(result.Hosts, ", "))
+	}
+
+	fmt.Println()
+}
+
+// displayDNSSECResult menampilkan chain of trust ke terminal
+func displayDNSSECResult(result *utils.DNSSECResult) {
+	fmt.Printf("  🌐 Domain: %s → %s\n", result.Domain, strings.ToUpper(result.Status))
+
+	for _, link := range result.Chain {
+		symbol := "✅"
+		if link.Status != utils.DNSSECSecure {
+			symbol = "❌"
+		}
+		fmt.Printf("    %s %s (%s)\n", symbol, link.Zone, link.Status)
+
+		for _, key := range link.Keys {
+			fmt.Printf("       🔑 %s %d %s", key.Role, key.KeyTag, key.Algorithm)
+			if key.KeySize > 0 {
+				fmt.Printf(" (%d bit)", key.KeySize)
+			}
+			fmt.Println()
+		}
+
+		if link.Error != "" {
+			fmt.Printf("       ⚠️  %s\n", link.Error)
+		}
+	}
+
+	if result.Denial != "" {
+		fmt.Printf("    📜 Denial of existence: %s\n", result.Denial)
+	}
+
+	fmt.Println()
+}
+
+// displayEmailSecurity menampilkan ringkasan postur email ke terminal
+func displayEmailSecurity(result *utils.EmailSecurity) {
+	fmt.Printf("  📧 Domain: %s\n", result.Domain)
+
+	if result.SPF != nil {
+		fmt.Printf("    SPF:     %s (%d lookup)\n", result.SPF.Record, result.SPF.LookupCount)
+	}
+	if result.DMARC != nil {
+		fmt.Printf("    DMARC:   p=%s pct=%d\n", result.DMARC.Policy, result.DMARC.Percent)
+	}
+	for _, dkim := range result.DKIM {
+		fmt.Printf("    DKIM:    %s (%s %d bit)\n", dkim.Selector, dkim.KeyType, dkim.KeySize)
+	}
+	if result.MTASTS != nil {
+		fmt.Printf("    MTA-STS: mode=%s\n", result.MTASTS.Mode)
+	}
+	if result.TLSRPT != nil {
+		fmt.Printf("    TLS-RPT: %s\n", strings.Join(result.TLSRPT.RUA, ", "))
+	}
+	if result.BIMI != nil {
+		fmt.Printf("    BIMI:    %s\n", result.BIMI.Logo)
+	}
+
+	displayFindings(result.Findings)
+	fmt.Println()
+}
+
+// displayFindings menampilkan daftar finding dengan severity
+func displayFindings(findings []utils.Finding) {
+	for _, finding := range findings {
+		fmt.Printf("    [%s] %s", strings.ToUpper(finding.Severity), finding.Title)
+		if finding.Detail != "" {
+			fmt.Printf(" - %s", finding.Detail)
+		}
+		fmt.Println()
+	}
+}
+
+// displayResolverComparison menampilkan jawaban per resolver dan inkonsistensi
+func displayResolverComparison(comparison *utils.ResolverComparison) {
+	status := "✅ konsisten"
+	if !comparison.Consistent {
+		status = "⚠️  tidak konsisten"
+	}
+	fmt.Printf("  🌐 %s %s: %s\n", comparison.Name, comparison.Type, status)
+
+	for _, answer := range comparison.Answers {
+		if answer.Error != "" {
+			fmt.Printf("    %-22s error: %s\n", answer.Server, answer.Error)
+			continue
+		}
+		fmt.Printf("    %-22s %-8s ttl=%d-%d %s\n", answer.Server, answer.Rcode,
+			answer.MinTTL, answer.MaxTTL, strings.Join(answer.Answers, ", "))
+	}
+
+	displayFindings(comparison.Findings)
+	fmt.Println()
+}
+
+// displayPTRSweepResult menampilkan inventaris IP → hostname ke terminal
+func displayPTRSweepResult(result *utils.PTRSweepResult) {
+	fmt.Printf("  🌐 Range: %s (%d IP, %d PTR, %s)\n",
+		strings.Join(result.Ranges, ", "), result.Scanned, len(result.Hosts), result.Duration)
+
+	for _, host := range result.Hosts {
+		symbol := "✅"
+		if !host.FCrDNS {
+			symbol = "⚠️ "
+		}
+		fmt.Printf("    %s %-39s %s\n", symbol, host.IP, strings.Join(host.Hostnames, ", "))
+	}
+
+	fmt.Println()
+}
+
+// displayECSResult menampilkan jawaban per subnet dan pemetaan edge ke terminal
+func displayECSResult(result *utils.ECSProbeResult) {
+	fmt.Printf("  🌐 %s %s\n", result.Name, result.Type)
+
+	for _, resolver := range result.Resolvers {
+		support := "ECS didukung"
+		if !resolver.Supported {
+			support = "ECS tidak didukung"
+		}
+		fmt.Printf("    📡 %s (%s)\n", resolver.Server, support)
+
+		for _, probe := range resolver.Probes {
+			label := probe.Subnet
+			if probe.Region != "" {
+				label = probe.Region + " " + probe.Subnet
+			}
+			if probe.Error != "" {
+				fmt.Printf("       %-24s error: %s\n", label, probe.Error)
+				continue
+			}
+			fmt.Printf("       %-24s scope=/%-3d %s\n", label, probe.Scope, strings.Join(probe.Answers, ", "))
+		}
+	}
+
+	if len(result.Edges) > 0 {
+		var edges []string
+		for edge := range result.Edges {
+			edges = append(edges, edge)
+		}
+		sort.Strings(edges)
+
+		fmt.Println("    🗺️  Edge per region:")
+		for _, edge := range edges {
+			fmt.Printf("       %-39s %s\n", edge, strings.Join(result.Edges[edge], ", "))
+		}
+	}
+
+	fmt.Println()
+}
+
+// displayDelegationResult menampilkan hasil audit delegasi NS ke terminal
+func displayDelegationResult(result *utils.DelegationResult) {
+	fmt.Printf("  🌐 Zone: %s (parent: %s)\n", result.Domain, result.ParentZone)
+
+	for _, ns := range result.Nameservers {
+		symbol := "✅"
+		if ns.Lame {
+			symbol = "❌"
+		} else if len(ns.LameIPs) > 0 || !ns.InParent || !ns.InChild {
+			symbol = "⚠️ "
+		}
+
+		fmt.Printf("    %s %s [%s]", symbol, ns.Name, strings.Join(ns.IPs, ", "))
+		if ns.Serial > 0 {
+			fmt.Printf(" serial=%d", ns.Serial)
+		}
+		if len(ns.ASNs) > 0 {
+			fmt.Printf(" AS%s", strings.Join(ns.ASNs, ",AS"))
+		}
+		fmt.Printf(" parent=%v child=%v\n", ns.InParent, ns.InChild)
+	}
+
+	displayFindings(result.Findings)
+	fmt.Println()
+}
+
+// displayZoneWalkResult menampilkan hasil zone walking ke terminal
+func displayZoneWalkResult(result *utils.ZoneWalkResult) {
+	denial := result.Denial
+	if denial == "" {
+		denial = "tidak ada"
+	}
+	fmt.Printf("  🌐 Zone: %s (denial: %s, %s)\n", result.Zone, denial, result.Duration)
+
+	for _, name := range result.Names {
+		fmt.Printf("    📛 %s\n", name)
+	}
+
+	if result.NSEC3 != nil {
+		salt := result.NSEC3.Salt
+		if salt == "" {
+			salt = "-"
+		}
+		fmt.Printf("    🔐 NSEC3: alg=%d iterasi=%d salt=%s opt-out=%v, %d hash\n",
+			result.NSEC3.Algorithm, result.NSEC3.Iterations, salt, result.NSEC3.OptOut, len(result.Hashes))
+	}
+
+	if result.Error != "" {
+		fmt.Printf("    ⚠️  %s\n", result.Error)
+	}
+
+	displayFindings(result.Findings)
+	fmt.Println()
+}
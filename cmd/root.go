@@ -22,6 +22,15 @@ Contoh penggunaan:
         Version: "1.0.0",
 }
 
+// banner ASCII yang ditampilkan di awal setiap command
+const banner = `
+╔═══════════════════════════════════════════════════════════════╗
+║  🛰️  VEKO GRID v1.0.0 - Network Exploration & Stealth Tool   ║
+║  📡 Anonymous Grid Scanning • TOR/Proxy Support • DNS/DoH    ║
+║  🔐 TLS Fingerprint Spoofing • Academic Research Tool        ║
+╚═══════════════════════════════════════════════════════════════╝
+`
+
 func Execute() error {
         return rootCmd.Execute()
 }
@@ -29,12 +38,11 @@ func Execute() error {
 func init() {
         rootCmd.CompletionOptions.DisableDefaultCmd = true
         
-        // Banner ASCII
-        fmt.Println(`
-╔═══════════════════════════════════════════════════════════════╗
-║  🛰️  VEKO GRID v1.0.0 - Network Exploration & Stealth Tool   ║
-║  📡 Anonymous Grid Scanning • TOR/Proxy Support • DNS/DoH    ║
-║  🔐 TLS Fingerprint Spoofing • Academic Research Tool        ║
-╚═══════════════════════════════════════════════════════════════╝
-`)
+        // Banner dicetak sebelum subcommand jalan, kecuali command yang outputnya
+        // dikonsumsi mesin (misal certs untuk cron/monitoring)
+        rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+                if cmd.Annotations["banner"] != "off" {
+                        fmt.Print(banner + "\n")
+                }
+        }
 }
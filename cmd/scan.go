@@ -18,26 +18,69 @@ var scanCmd = &cobra.Command{
 	
 Fitur utama:
 • Port scanning dan ping detection
-• DNS resolution (A/AAAA/MX/NS/CNAME)
+• DNS resolution (A/AAAA/MX/NS/CNAME, opsional SOA/CAA/SRV/HTTPS/DS/TLSA via --records)
+• Subdomain enumeration dari wordlist (--wordlist)
+• Deteksi wildcard DNS per zone (hasil ditandai wildcard, --exclude-wildcard)
+• Zone transfer (AXFR/IXFR) exposure check (--axfr)
+• Audit delegasi NS: lame server, parent/child mismatch, glue (--ns-audit)
+• DNSSEC chain-of-trust validation (--dnssec)
+• Email security posture: SPF, DMARC, DKIM, MTA-STS, TLS-RPT, BIMI (--email)
+• Dangling CNAME dan subdomain takeover detection (--takeover)
+• Perbandingan jawaban antar resolver (--compare-resolvers)
 • Traceroute dan CDN lookup
-• Support TOR dan proxy rotation
+• Support TOR dan proxy rotation (DNS ikut lewat proxy, --strict-dns tanpa fallback)
 • Random delay untuk stealth scanning
-• TLS fingerprint randomization`,
+• Fingerprint TLS di setiap port terbuka yang berbicara TLS (per IP:port, SNI dari hostname), ClientHello random secara default (--random-tls=false untuk konfigurasi permissive tetap)
+• Session ticket, resumption, 0-RTT yang diiklankan dan HSTS per port TLS (--tls-session)
+• Analisis certificate chain dan validasi terhadap system roots/CA bundle (--ca-bundle)
+• Analisis STARTTLS (SMTP, IMAP, POP3, FTP, XMPP, LDAP, PostgreSQL) di port plaintext yang terbuka
+• Perbandingan SNI untuk deteksi default vhost dan site tersembunyi, SAN sebagai kandidat target (--sni-check, --follow-sans)
+• Enumerasi versi SSL/TLS dan cipher suite dengan flag konfigurasi lemah (--tls-enum)
+• Fingerprint JARM aktif per port TLS untuk clustering implementasi TLS (--jarm)
+• Findings miskonfigurasi TLS dengan severity (hostname, chain, expiry, kunci, SHA-1, versi, cipher) di output dan grid
+  (SSLv3/TLS 1.0/1.1 diuji dengan --tls-legacy atau --tls-enum; cipher di luar yang dinegosiasikan butuh --tls-enum)`,
 	RunE: runScan,
 }
 
 var (
-	inputFile   string
-	outputFile  string
-	proxyAddr   string
-	useTor      bool
-	delayRange  string
-	timeout     int
-	dnsMode     string
-	silent      bool
-	jsonOutput  bool
-	debugMode   bool
-	maxThreads  int
+	inputFile     string
+	outputFile    string
+	proxyAddr     string
+	useTor        bool
+	delayRange    string
+	timeout       int
+	dnsMode       string
+	silent        bool
+	jsonOutput    bool
+	debugMode     bool
+	maxThreads    int
+	wordlist      string
+	enumDepth     int
+	checkAXFR     bool
+	checkDNSSEC   bool
+	trustAnchor   string
+	checkEmail    bool
+	dkimSelectors []string
+	recordTypes   []string
+	checkTakeover bool
+	takeoverData  string
+	resolvers     []string
+	compareDNS    bool
+	ednsBuffer    int
+	dnsRateLimit  int
+	strictDNS     bool
+	auditNS       bool
+	noWildcard    bool
+	caBundle      string
+	tlsEnum       bool
+	extraPorts    []int
+	sniCheck      bool
+	followSANs    bool
+	jarm          bool
+	randomTLS     bool
+	tlsLegacy     bool
+	tlsSession    bool
+	axfrMaxHosts  int
 )
 
 func init() {
@@ -50,25 +93,67 @@ func init() {
 	// Anonymity flags
 	scanCmd.Flags().StringVarP(&proxyAddr, "proxy", "p", "", "Proxy address (socks5://127.0.0.1:9050)")
 	scanCmd.Flags().BoolVar(&useTor, "tor", false, "Gunakan TOR untuk anonimitas")
+	scanCmd.Flags().BoolVar(&strictDNS, "strict-dns", false, "Batalkan query DNS jika proxy/TOR gagal (tanpa fallback ke direct DNS)")
 	
 	// Stealth flags
 	scanCmd.Flags().StringVar(&delayRange, "delay", "100-500", "Random delay antar request (ms)")
+	scanCmd.Flags().BoolVar(&randomTLS, "random-tls", true, "ClientHello random per koneksi, fallback ke konfigurasi permissive jika handshake gagal (--random-tls=false untuk fingerprint tetap)")
 	scanCmd.Flags().IntVar(&timeout, "timeout", 5, "Timeout koneksi (detik)")
 	scanCmd.Flags().StringVar(&dnsMode, "dns", "default", "DNS mode: default/doh")
+	scanCmd.Flags().StringSliceVar(&resolvers, "resolvers", nil, "Daftar DNS resolver (default: 8.8.8.8,1.1.1.1,9.9.9.9,208.67.222.222)")
+	scanCmd.Flags().BoolVar(&compareDNS, "compare-resolvers", false, "Bandingkan jawaban A/AAAA antar resolver (deteksi split-horizon/tampering)")
+	scanCmd.Flags().StringSliceVar(&recordTypes, "records", nil, "Record types yang di-query (A,AAAA,CNAME,MX,NS,TXT,SOA,CAA,SRV,HTTPS,SVCB,DS,TLSA atau all); A/AAAA untuk port scan selalu di-resolve")
+	scanCmd.Flags().IntVar(&ednsBuffer, "edns-buffer", utils.DefaultEDNSBufferSize, "Ukuran buffer UDP EDNS0 (byte), fallback TCP jika truncated")
+	scanCmd.Flags().IntVar(&dnsRateLimit, "dns-rate", 0, "Maksimum query DNS per detik untuk seluruh scan (0 = tanpa batas)")
 	
 	// Output flags
 	scanCmd.Flags().BoolVar(&silent, "silent", false, "Mode silent (minimal output)")
 	scanCmd.Flags().BoolVar(&jsonOutput, "json", false, "Output dalam format JSON ke stdout")
 	scanCmd.Flags().BoolVar(&debugMode, "debug", false, "Enable debug logging")
+	scanCmd.Flags().BoolVar(&noWildcard, "exclude-wildcard", false, "Buang target yang hanya cocok dengan wildcard DNS dari tampilan, statistik dan file output")
 	
 	// Performance flags
 	scanCmd.Flags().IntVar(&maxThreads, "threads", 10, "Maksimum thread concurrent")
+	scanCmd.Flags().IntSliceVar(&extraPorts, "ports", nil, "Port tambahan selain port umum (misal 4443,9443); TLS dicoba di setiap port terbuka")
+
+	// Enumeration flags
+	scanCmd.Flags().StringVar(&wordlist, "wordlist", "", "Wordlist untuk subdomain enumeration (hasil ikut di-scan)")
+	scanCmd.Flags().IntVar(&enumDepth, "enum-depth", 1, "Batas kedalaman subdomain enumeration")
+	scanCmd.Flags().BoolVar(&checkAXFR, "axfr", false, "Cek AXFR/IXFR zone transfer ke setiap nameserver (sekali per apex zone, host hasil transfer ikut di-scan sesuai --enum-depth)")
+	scanCmd.Flags().IntVar(&axfrMaxHosts, "axfr-max-hosts", 256, "Maksimum host hasil zone transfer yang ikut di-scan per zone (0 = tanpa batas)")
+	scanCmd.Flags().BoolVar(&auditNS, "ns-audit", false, "Audit delegasi NS (lame, parent/child mismatch, serial, ASN, glue)")
+
+	// DNSSEC flags
+	scanCmd.Flags().BoolVar(&checkDNSSEC, "dnssec", false, "Validasi DNSSEC chain of trust")
+	scanCmd.Flags().StringVar(&trustAnchor, "trust-anchor", "", "File trust anchor DS/DNSKEY (default: root KSK IANA)")
+
+	// Email security flags
+	scanCmd.Flags().BoolVar(&checkEmail, "email", false, "Analisis SPF/DMARC/DKIM/MTA-STS/TLS-RPT/BIMI")
+	scanCmd.Flags().StringSliceVar(&dkimSelectors, "dkim-selectors", nil, "Daftar DKIM selector untuk probing (default: selector umum)")
+
+	// Takeover flags
+	scanCmd.Flags().BoolVar(&checkTakeover, "takeover", false, "Cek dangling CNAME dan subdomain takeover")
+	scanCmd.Flags().StringVar(&takeoverData, "takeover-fingerprints", "", "File JSON fingerprint service takeover (default: data bawaan)")
+
+	// TLS flags
+	scanCmd.Flags().StringVar(&caBundle, "ca-bundle", "", "CA bundle PEM untuk validasi certificate chain (default: system roots)")
+	scanCmd.Flags().BoolVar(&sniCheck, "sni-check", false, "Bandingkan sertifikat dengan SNI benar, tanpa SNI dan SNI palsu (default vhost/site tersembunyi)")
+	scanCmd.Flags().BoolVar(&followSANs, "follow-sans", false, "Scan SAN dari sertifikat sebagai target baru (butuh --sni-check, hanya registrable domain target input, dibatasi --enum-depth)")
+	scanCmd.Flags().BoolVar(&tlsEnum, "tls-enum", false, "Enumerasi versi protokol (SSLv3-TLS 1.3) dan cipher suite beserta urutan preferensi")
+	scanCmd.Flags().BoolVar(&tlsLegacy, "tls-legacy", false, "Probe SSLv3/TLS 1.0/TLS 1.1 di setiap port TLS (3 ClientHello tambahan, tercakup oleh --tls-enum)")
+	scanCmd.Flags().BoolVar(&tlsSession, "tls-session", false, "Uji session ticket TLS 1.3, resumption, 0-RTT dan HSTS (tunggu ticket, handshake kedua dan request HTTP per port TLS)")
+	scanCmd.Flags().BoolVar(&jarm, "jarm", false, "Fingerprint JARM aktif (10 ClientHello) untuk mengidentifikasi implementasi TLS server")
 
 	// Required flags
 	scanCmd.MarkFlagRequired("input")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
+	// Kandidat SAN berasal dari perbandingan SNI
+	if followSANs && !sniCheck {
+		return fmt.Errorf("❌ --follow-sans membutuhkan --sni-check")
+	}
+
 	// Initialize logger
 	logger := utils.NewLogger(debugMode, silent)
 	
@@ -78,17 +163,44 @@ func runScan(cmd *cobra.Command, args []string) error {
 
 	// Baca konfigurasi
 	cfg := &config.Config{
-		InputFile:   inputFile,
-		OutputFile:  outputFile,
-		ProxyAddr:   proxyAddr,
-		UseTor:      useTor,
-		DelayRange:  delayRange,
-		Timeout:     timeout,
-		DNSMode:     dnsMode,
-		Silent:      silent,
-		JSONOutput:  jsonOutput,
-		Debug:       debugMode,
-		MaxThreads:  maxThreads,
+		InputFile:     inputFile,
+		OutputFile:    outputFile,
+		ProxyAddr:     proxyAddr,
+		UseTor:        useTor,
+		DelayRange:    delayRange,
+		Timeout:       timeout,
+		DNSMode:       dnsMode,
+		Silent:        silent,
+		JSONOutput:    jsonOutput,
+		Debug:         debugMode,
+		MaxThreads:    maxThreads,
+		Wordlist:      wordlist,
+		EnumDepth:     enumDepth,
+		CheckAXFR:     checkAXFR,
+		CheckDNSSEC:   checkDNSSEC,
+		TrustAnchor:   trustAnchor,
+		CheckEmail:    checkEmail,
+		DKIMSelectors: dkimSelectors,
+		RecordTypes:   recordTypes,
+		CheckTakeover: checkTakeover,
+		TakeoverData:  takeoverData,
+		Resolvers:     resolvers,
+		CompareDNS:    compareDNS,
+		EDNSBuffer:    ednsBuffer,
+		DNSRate:       dnsRateLimit,
+		StrictDNS:     strictDNS,
+		AuditNS:       auditNS,
+		NoWildcard:    noWildcard,
+		CABundle:      caBundle,
+		TLSEnum:       tlsEnum,
+		Ports:         extraPorts,
+		SNICheck:      sniCheck,
+		FollowSANs:    followSANs,
+		JARM:          jarm,
+		RandomTLS:     randomTLS,
+		TLSLegacy:     tlsLegacy,
+		TLSSession:    tlsSession,
+		AXFRMaxHosts:  axfrMaxHosts,
 	}
 
 	// Validasi file input
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"veko-grid/config"
+	"veko-grid/utils"
+)
+
+var tlsCmd = &cobra.Command{
+	Use:   "tls",
+	Short: "🔐 Audit konfigurasi TLS server",
+	Long: `🔐 TLS command berisi tool audit TLS yang berdiri sendiri
+di luar grid scanning.
+
+Subcommand:
+• enum - Enumerasi versi protokol dan cipher suite yang didukung server
+• jarm - Fingerprint JARM aktif untuk clustering implementasi TLS`,
+}
+
+var tlsEnumCmd = &cobra.Command{
+	Use:   "enum",
+	Short: "🧮 Enumerasi versi protokol dan cipher suite",
+	Long: `🧮 Enum menguji setiap versi protokol (SSL 3.0, TLS 1.0-1.3) dan setiap
+cipher suite secara deterministik dengan ClientHello mentah, sehingga versi
+lama yang tidak didukung crypto/tls tetap terdeteksi.
+
+Cipher yang dipilih server dibuang lalu ClientHello dikirim ulang sampai server
+menolak, menghasilkan matriks lengkap beserta urutan preferensi server. Versi
+deprecated, cipher insecure (NULL, anon, EXPORT, RC4, DES) dan cipher lemah
+(CBC, 3DES, RSA key exchange) dilaporkan sebagai findings.
+
+Contoh penggunaan:
+  veko-grid tls enum --target example.com --target mail.example.com:465`,
+	RunE: runTLSEnum,
+}
+
+var tlsJARMCmd = &cobra.Command{
+	Use:   "jarm",
+	Short: "🧬 Fingerprint JARM implementasi TLS server",
+	Long: `🧬 JARM mengirim 10 ClientHello yang dirancang khusus (variasi versi, urutan
+cipher, ALPN dan extension) lalu meng-hash cipher, versi dan extension dari
+setiap ServerHello menjadi fingerprint 62 karakter yang kompatibel dengan JARM.
+
+Server dengan stack dan konfigurasi TLS yang sama menghasilkan fingerprint yang
+sama, sehingga hasil dikelompokkan per fingerprint untuk menemukan server yang
+menyimpang dari fleet.
+
+Contoh penggunaan:
+  veko-grid tls jarm --target example.com --target 10.0.0.5:8443
+  veko-grid tls jarm --input fleet.txt -o jarm.json`,
+	RunE: runTLSJARM,
+}
+
+var (
+	tlsOutputFile string
+	tlsSilent     bool
+	tlsDebug      bool
+
+	tlsEnumTargets []string
+
+	tlsJARMTargets []string
+	tlsJARMInput   string
+)
+
+func init() {
+	rootCmd.AddCommand(tlsCmd)
+	tlsCmd.AddCommand(tlsEnumCmd)
+
+	// Flags bersama untuk semua subcommand TLS
+	tlsCmd.PersistentFlags().StringVarP(&tlsOutputFile, "output", "o", "", "File output JSON (opsional)")
+	tlsCmd.PersistentFlags().BoolVar(&tlsSilent, "silent", false, "Mode silent (minimal output)")
+	tlsCmd.PersistentFlags().BoolVar(&tlsDebug, "debug", false, "Enable debug logging")
+
+	// Enum flags
+	tlsEnumCmd.Flags().StringSliceVarP(&tlsEnumTargets, "target", "t", nil, "Target host atau host:port (default port 443)")
+	tlsEnumCmd.MarkFlagRequired("target")
+
+	// JARM flags
+	tlsCmd.AddCommand(tlsJARMCmd)
+	tlsJARMCmd.Flags().StringSliceVarP(&tlsJARMTargets, "target", "t", nil, "Target host atau host:port (default port 443)")
+	tlsJARMCmd.Flags().StringVarP(&tlsJARMInput, "input", "i", "", "File berisi daftar target (host atau host:port)")
+}
+
+func runTLSEnum(cmd *cobra.Command, args []string) error {
+	logger := utils.NewLogger(tlsDebug, tlsSilent)
+	spoofer := utils.NewFingerprintSpoofer(logger)
+
+	var results []*utils.TLSEnumResult
+	for _, target := range tlsEnumTargets {
+		result := spoofer.EnumerateTLS(target)
+		results = append(results, result)
+
+		if !tlsSilent {
+			displayTLSEnumResult(result)
+		}
+	}
+
+	return saveTLSResults(logger, results)
+}
+
+func runTLSJARM(cmd *cobra.Command, args []string) error {
+	logger := utils.NewLogger(tlsDebug, tlsSilent)
+	spoofer := utils.NewFingerprintSpoofer(logger)
+
+	targets := tlsJARMTargets
+	if tlsJARMInput != "" {
+		fileTargets, err := readTargetsFromFile(tlsJARMInput)
+		if err != nil {
+			return fmt.Errorf("❌ Error membaca file targets: %v", err)
+		}
+		targets = append(targets, fileTargets...)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("❌ Tidak ada target, gunakan --target atau --input")
+	}
+
+	var results []*utils.JARMResult
+	for _, target := range targets {
+		result := spoofer.JARM(target)
+		results = append(results, result)
+
+		if !tlsSilent {
+			if result.Error != "" {
+				fmt.Printf("  ❌ %-30s %s\n", result.Address, result.Error)
+			} else {
+				fmt.Printf("  🧬 %-30s %s\n", result.Address, result.Fingerprint)
+			}
+		}
+	}
+
+	report := utils.BuildJARMReport(results)
+	if !tlsSilent {
+		displayJARMClusters(report.Clusters)
+	}
+
+	return saveTLSResults(logger, report)
+}
+
+// saveTLSResults menyimpan hasil subcommand TLS jika --output diisi
+func saveTLSResults(logger *utils.Logger, results interface{}) error {
+	if tlsOutputFile == "" {
+		return nil
+	}
+
+	cfg := &config.Config{
+		OutputFile: tlsOutputFile,
+		Silent:     tlsSilent,
+	}
+
+	outputHandler := utils.NewOutputHandler(cfg, logger)
+	if err := outputHandler.SaveResults(results); err != nil {
+		return fmt.Errorf("❌ Error menyimpan hasil: %v", err)
+	}
+
+	return nil
+}
+
+// displayTLSEnumResult menampilkan matriks versi dan cipher suite ke terminal
+func displayTLSEnumResult(result *utils.TLSEnumResult) {
+	fmt.Printf("  🌐 %s (%s)\n", result.Address, result.Duration)
+
+	if result.Error != "" {
+		fmt.Printf("    ❌ %s\n\n", result.Error)
+		return
+	}
+
+	for _, protocol := range result.Protocols {
+		if !protocol.Supported {
+			fmt.Printf("    ➖ %s\n", protocol.Version)
+			continue
+		}
+
+		order := "urutan client"
+		if protocol.ServerPreference || len(protocol.Ciphers) == 1 {
+			order = "urutan server"
+		}
+		fmt.Printf("    ✅ %s (%d cipher, %s)\n", protocol.Version, len(protocol.Ciphers), order)
+
+		for _, cipher := range protocol.Ciphers {
+			symbol := "  "
+			switch cipher.Strength {
+			case utils.CipherInsecure:
+				symbol = "❌"
+			case utils.CipherWeak:
+				symbol = "⚠️ "
+			}
+			fmt.Printf("       %s %-45s %s\n", symbol, cipher.Name, strings.Join(cipher.Issues, ","))
+		}
+	}
+
+	displayFindings(result.Findings)
+	fmt.Println()
+}
+
+// displayJARMClusters menampilkan kelompok address per fingerprint JARM
+func displayJARMClusters(clusters []utils.JARMCluster) {
+	if len(clusters) == 0 {
+		return
+	}
+
+	fmt.Printf("\n  📊 %d cluster JARM\n", len(clusters))
+	for _, cluster := range clusters {
+		fmt.Printf("    %s (%d)\n", cluster.Fingerprint, len(cluster.Addresses))
+		for _, address := range cluster.Addresses {
+			fmt.Printf("       %s\n", address)
+		}
+	}
+}
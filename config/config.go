@@ -8,17 +8,44 @@ import (
 
 // Config menyimpan konfigurasi untuk Veko Grid
 type Config struct {
-	InputFile   string
-	OutputFile  string
-	ProxyAddr   string
-	UseTor      bool
-	DelayRange  string
-	Timeout     int
-	DNSMode     string
-	Silent      bool
-	JSONOutput  bool
-	Debug       bool
-	MaxThreads  int
+	InputFile     string
+	OutputFile    string
+	ProxyAddr     string
+	UseTor        bool
+	DelayRange    string
+	Timeout       int
+	DNSMode       string
+	Silent        bool
+	JSONOutput    bool
+	Debug         bool
+	MaxThreads    int
+	Wordlist      string
+	EnumDepth     int
+	CheckAXFR     bool
+	CheckDNSSEC   bool
+	TrustAnchor   string
+	CheckEmail    bool
+	DKIMSelectors []string
+	RecordTypes   []string
+	CheckTakeover bool
+	TakeoverData  string
+	Resolvers     []string
+	CompareDNS    bool
+	EDNSBuffer    int
+	DNSRate       int
+	StrictDNS     bool
+	AuditNS       bool
+	NoWildcard    bool
+	CABundle      string
+	TLSEnum       bool
+	Ports         []int
+	SNICheck      bool
+	FollowSANs    bool
+	JARM          bool
+	RandomTLS     bool
+	TLSLegacy     bool
+	TLSSession    bool
+	AXFRMaxHosts  int
 }
 
 // GetDelayRange mengparsing delay range menjadi min dan max milliseconds
@@ -42,6 +69,11 @@ func (c *Config) GetDelayRange() (time.Duration, time.Duration, error) {
 		   time.Duration(max) * time.Millisecond, nil
 }
 
+// IsProxyEnabled mengecek apakah traffic harus lewat proxy atau TOR
+func (c *Config) IsProxyEnabled() bool {
+	return c.UseTor || c.ProxyAddr != ""
+}
+
 // GetTimeout mengkonversi timeout ke time.Duration
 func (c *Config) GetTimeout() time.Duration {
 	return time.Duration(c.Timeout) * time.Second
@@ -56,3 +88,8 @@ func (c *Config) IsTorEnabled() bool {
 func (c *Config) IsDoHEnabled() bool {
 	return strings.ToLower(c.DNSMode) == "doh"
 }
+
+// IsEnumEnabled mengecek apakah subdomain enumeration diaktifkan
+func (c *Config) IsEnumEnabled() bool {
+	return c.Wordlist != "" && c.EnumDepth > 0
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"veko-grid/utils"
+)
+
+// implicitTLSPorts adalah port yang seharusnya langsung berbicara TLS, sehingga
+// handshake gagal di port ini dilaporkan sebagai UNKNOWN dan bukan diabaikan
+var implicitTLSPorts = map[int]bool{443: true, 465: true, 636: true, 853: true, 993: true, 995: true, 8443: true}
+
+// ScanCertificates mengumpulkan sertifikat leaf dari setiap port TLS (implicit dan STARTTLS)
+// yang terbuka di setiap target, tanpa tahap DNS/CDN/traceroute dari scan penuh.
+// Target yang tidak menghasilkan sertifikat tetap muncul sebagai entry UNKNOWN
+func (s *Scanner) ScanCertificates(targets []string) []*utils.CertificateExpiry {
+	var entries []*utils.CertificateExpiry
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	semaphore := make(chan struct{}, s.config.MaxThreads)
+
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			found := s.scanTargetCertificates(target)
+
+			mutex.Lock()
+			entries = append(entries, found...)
+			mutex.Unlock()
+		}(target)
+	}
+
+	wg.Wait()
+	return entries
+}
+
+// scanTargetCertificates mengumpulkan sertifikat dari setiap alamat A dan AAAA target,
+// karena IP di belakang satu nama bisa memakai sertifikat yang berbeda
+func (s *Scanner) scanTargetCertificates(target string) []*utils.CertificateExpiry {
+	ips, err := s.resolveCertificateAddresses(target)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("%s %v", target, err))
+		return []*utils.CertificateExpiry{utils.NewCertificateExpiryError(target, "", "", err.Error())}
+	}
+
+	var entries, unknown []*utils.CertificateExpiry
+	var failures []string
+
+	for _, ip := range ips {
+		found, failed, reasons := s.scanAddressCertificates(target, ip)
+		entries = append(entries, found...)
+		unknown = append(unknown, failed...)
+		failures = append(failures, reasons...)
+	}
+
+	entries = append(entries, unknown...)
+	if len(entries) > 0 {
+		return entries
+	}
+
+	// Tidak ada sertifikat sama sekali: laporkan alasannya agar target tidak hilang dari laporan
+	reason := "tidak ada port TLS terbuka"
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		reason = "handshake TLS gagal di semua port: " + strings.Join(failures, "; ")
+	}
+	s.logger.Debug(fmt.Sprintf("%s: %s", target, reason))
+
+	return []*utils.CertificateExpiry{utils.NewCertificateExpiryError(target, strings.Join(ips, ", "), "", reason)}
+}
+
+// resolveCertificateAddresses mengembalikan target itu sendiri jika berupa IP, atau
+// gabungan record A dan AAAA
+func (s *Scanner) resolveCertificateAddresses(target string) ([]string, error) {
+	if utils.ValidateIP(target) {
+		return []string{target}, nil
+	}
+
+	ips, errA := s.dnsResolver.LookupA(target)
+	ipv6, errAAAA := s.dnsResolver.LookupAAAA(target)
+	ips = append(ips, ipv6...)
+
+	if len(ips) == 0 {
+		if errA == nil {
+			errA = errAAAA
+		}
+		if errA != nil {
+			return nil, fmt.Errorf("tidak dapat di-resolve: %v", errA)
+		}
+		return nil, fmt.Errorf("tidak dapat di-resolve")
+	}
+	return ips, nil
+}
+
+// scanAddressCertificates menjalankan port scan lalu handshake TLS leaf-only dengan
+// konfigurasi permissive di setiap port terbuka satu IP. Mengembalikan sertifikat,
+// entry UNKNOWN untuk port yang seharusnya TLS, dan alasan setiap handshake yang gagal
+func (s *Scanner) scanAddressCertificates(target, ip string) ([]*utils.CertificateExpiry, []*utils.CertificateExpiry, []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetTimeout())
+	defer cancel()
+
+	openPorts, _ := s.scanPorts(ctx, ip)
+
+	var entries, unknown []*utils.CertificateExpiry
+	var failures []string
+
+	for _, port := range openPorts {
+		if _, plaintext := utils.STARTTLSPorts[port]; plaintext {
+			continue
+		}
+
+		address := net.JoinHostPort(ip, strconv.Itoa(port))
+		fingerprint, err := s.fingerprint.LeafCertificateAddress(address, target, s.config.GetTimeout())
+		if err != nil {
+			s.logger.Debug(fmt.Sprintf("TLS handshake gagal untuk %s: %v", address, err))
+			failures = append(failures, fmt.Sprintf("%s: %v", address, err))
+			if implicitTLSPorts[port] || containsPort(s.config.Ports, port) {
+				unknown = append(unknown, utils.NewCertificateExpiryError(target, address, "tls", "handshake TLS gagal: "+err.Error()))
+			}
+			continue
+		}
+
+		if entry := utils.NewCertificateExpiry(target, address, "tls", fingerprint); entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	for address, starttls := range s.performSTARTTLS(target, ip, openPorts) {
+		if entry := utils.NewCertificateExpiry(target, address, starttls.Protocol, starttls.Fingerprint); entry != nil {
+			entries = append(entries, entry)
+		} else if starttls.Offered {
+			// Server menawarkan STARTTLS tapi upgrade atau handshake gagal
+			unknown = append(unknown, utils.NewCertificateExpiryError(target, address, starttls.Protocol, "STARTTLS gagal: "+starttls.Error))
+		}
+	}
+
+	return entries, unknown, failures
+}
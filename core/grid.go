@@ -109,6 +109,15 @@ func (g *Grid) getStatusSymbol(target string, resultMap map[string]*ScanResult)
 		return "❌" // Failed
 	}
 
+	if result.Wildcard {
+		return "🃏" // Jawaban dari wildcard DNS
+	}
+
+	// Miskonfigurasi TLS lebih penting daripada jumlah port terbuka
+	if symbol := severitySymbol(utils.HighestSeverity(result.TLSFindings)); symbol != "" {
+		return symbol
+	}
+
 	// Success dengan gradasi berdasarkan hasil
 	if len(result.OpenPorts) > 5 {
 		return "🔴" // Banyak port terbuka
@@ -119,10 +128,22 @@ func (g *Grid) getStatusSymbol(target string, resultMap map[string]*ScanResult)
 	}
 }
 
+// severitySymbol mengembalikan warna untuk severity finding TLS (kosong untuk low/info)
+func severitySymbol(severity string) string {
+	switch severity {
+	case utils.SeverityCritical, utils.SeverityHigh:
+		return "🟣" // TLS bermasalah serius
+	case utils.SeverityMedium:
+		return "🟠" // TLS lemah
+	}
+	return ""
+}
+
 // displayLegend menampilkan legend untuk grid
 func (g *Grid) displayLegend() {
 	fmt.Println("\n📋 Legend:")
-	fmt.Println("  ⏳ Pending   🟢 Host Active   🟡 Some Ports   🔴 Many Ports   ❌ Failed")
+	fmt.Println("  ⏳ Pending   🟢 Host Active   🟡 Some Ports   🔴 Many Ports   ❌ Failed   🃏 Wildcard")
+	fmt.Println("  🟣 TLS Critical/High   🟠 TLS Medium")
 }
 
 // displayStats menampilkan statistik scanning
@@ -131,14 +152,26 @@ func (g *Grid) displayStats(results []*ScanResult) {
 		return
 	}
 
-	var successful, failed int
+	var successful, failed, wildcard, tlsSerious, tlsWeak int
 	var totalPorts int
 	var totalScanTime time.Duration
 	var avgScanTime time.Duration
+	var totalDNSTime time.Duration
 
 	portStats := make(map[int]int)
 
 	for _, result := range results {
+		if result.Wildcard {
+			wildcard++
+		}
+
+		switch utils.HighestSeverity(result.TLSFindings) {
+		case utils.SeverityCritical, utils.SeverityHigh:
+			tlsSerious++
+		case utils.SeverityMedium:
+			tlsWeak++
+		}
+
 		if result.Error != "" {
 			failed++
 		} else {
@@ -151,6 +184,7 @@ func (g *Grid) displayStats(results []*ScanResult) {
 			}
 		}
 		totalScanTime += result.ScanTime
+		totalDNSTime += result.DNSTime
 	}
 
 	if len(results) > 0 {
@@ -161,8 +195,15 @@ func (g *Grid) displayStats(results []*ScanResult) {
 	fmt.Printf("  🎯 Total Targets: %d\n", len(results))
 	fmt.Printf("  ✅ Successful: %d (%.1f%%)\n", successful, float64(successful)/float64(len(results))*100)
 	fmt.Printf("  ❌ Failed: %d (%.1f%%)\n", failed, float64(failed)/float64(len(results))*100)
+	if wildcard > 0 {
+		fmt.Printf("  🃏 Wildcard: %d (%.1f%%)\n", wildcard, float64(wildcard)/float64(len(results))*100)
+	}
+	if tlsSerious+tlsWeak > 0 {
+		fmt.Printf("  🔐 TLS Issues: %d critical/high, %d medium\n", tlsSerious, tlsWeak)
+	}
 	fmt.Printf("  🔓 Total Open Ports: %d\n", totalPorts)
 	fmt.Printf("  ⏱️  Average Scan Time: %v\n", avgScanTime.Round(time.Millisecond))
+	fmt.Printf("  📡 Average DNS Time: %v\n", (totalDNSTime / time.Duration(len(results))).Round(time.Millisecond))
 
 	// Top ports
 	g.displayTopPorts(portStats)
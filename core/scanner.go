@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,28 +24,69 @@ type Scanner struct {
 	dnsResolver  *utils.DNSResolver
 	fingerprint  *utils.FingerprintSpoofer
 	grid         *Grid
+	wordlist     []string
+
+	// Cache wildcard signature per parent zone
+	wildcards     map[string]*utils.WildcardSignature
+	wildcardMutex sync.Mutex
+
+	// Apex zone yang sudah dicoba zone transfer
+	axfrZones map[string]bool
+	axfrMutex sync.Mutex
+
+	// Registrable domain target input, batas scope SAN yang diikuti --follow-sans
+	sanScope map[string]bool
 }
 
 // ScanResult menyimpan hasil scanning untuk satu target
 type ScanResult struct {
-	Target      string                 `json:"target"`
-	IP          string                 `json:"ip,omitempty"`
-	Timestamp   time.Time              `json:"timestamp"`
-	DNSRecords  map[string][]string    `json:"dns_records,omitempty"`
-	OpenPorts   []int                  `json:"open_ports,omitempty"`
-	Services    map[int]string         `json:"services,omitempty"`
-	Traceroute  []string               `json:"traceroute,omitempty"`
-	CDNInfo     map[string]interface{} `json:"cdn_info,omitempty"`
-	TLSInfo     map[string]interface{} `json:"tls_info,omitempty"`
-	Error       string                 `json:"error,omitempty"`
-	ScanTime    time.Duration          `json:"scan_time"`
+	Target        string                  `json:"target"`
+	IP            string                  `json:"ip,omitempty"`
+	Timestamp     time.Time               `json:"timestamp"`
+	DNSRecords    map[string][]string     `json:"dns_records,omitempty"`
+	DNSErrors     map[string]string       `json:"dns_errors,omitempty"`
+	OpenPorts     []int                   `json:"open_ports,omitempty"`
+	Services      map[int]string          `json:"services,omitempty"`
+	Traceroute    []string                `json:"traceroute,omitempty"`
+	CDNInfo       map[string]interface{}  `json:"cdn_info,omitempty"`
+	TLSInfo       map[string]interface{}  `json:"tls_info,omitempty"` // Deprecated: gunakan TLS, diisi dari port TLS utama
+	Error         string                  `json:"error,omitempty"`
+	ScanTime      time.Duration           `json:"scan_time"`
+	DNSTime       time.Duration           `json:"dns_time"`
+	Wildcard      bool                    `json:"wildcard,omitempty"`
+	DNSSEC        *utils.DNSSECResult     `json:"dnssec,omitempty"`
+	EmailSecurity *utils.EmailSecurity    `json:"email_security,omitempty"`
+	Takeover      *utils.TakeoverResult   `json:"takeover,omitempty"`
+	Delegation    *utils.DelegationResult `json:"delegation,omitempty"`
+
+	// Hasil TLS per IP:port: handshake langsung, enumerasi, dan upgrade STARTTLS
+	TLS      map[string]*utils.TLSFingerprint `json:"tls,omitempty"`
+	TLSEnum  map[string]*utils.TLSEnumResult  `json:"tls_enum,omitempty"`
+	STARTTLS map[string]*utils.STARTTLSResult `json:"starttls,omitempty"`
+	JARM     map[string]*utils.JARMResult     `json:"jarm,omitempty"`
+
+	// Miskonfigurasi TLS (sertifikat, chain, versi, cipher) di semua port TLS dan STARTTLS
+	TLSFindings []utils.Finding `json:"tls_findings,omitempty"`
+
+	// SAN dari sertifikat yang belum menjadi target (kandidat target baru)
+	SANCandidates []string `json:"san_candidates,omitempty"`
+
+	ResolverComparison []*utils.ResolverComparison `json:"resolver_comparison,omitempty"`
+
+	// Target discovery (subdomain enumeration, zone transfer)
+	DiscoveredFrom string                    `json:"discovered_from,omitempty"`
+	Depth          int                       `json:"depth,omitempty"`
+	Subdomains     *utils.EnumResult         `json:"subdomains,omitempty"`
+	ZoneTransfer   *utils.ZoneTransferResult `json:"zone_transfer,omitempty"`
 }
 
 // NewScanner membuat instance Scanner baru
 func NewScanner(cfg *config.Config, logger *utils.Logger) (*Scanner, error) {
 	scanner := &Scanner{
-		config: cfg,
-		logger: logger,
+		config:    cfg,
+		logger:    logger,
+		wildcards: make(map[string]*utils.WildcardSignature),
+		axfrZones: make(map[string]bool),
 	}
 
 	// Initialize proxy manager
@@ -58,9 +102,62 @@ func NewScanner(cfg *config.Config, logger *utils.Logger) (*Scanner, error) {
 		return nil, fmt.Errorf("failed to initialize DNS resolver: %v", err)
 	}
 	scanner.dnsResolver = dnsResolver
+	dnsResolver.SetServers(cfg.Resolvers)
+	dnsResolver.SetTimeout(cfg.GetTimeout())
+	dnsResolver.SetEDNSBufferSize(cfg.EDNSBuffer)
+	dnsResolver.SetRateLimit(cfg.DNSRate)
+
+	// DNS lewat proxy/TOR agar domain yang di-scan tidak bocor dari IP asli
+	if cfg.IsProxyEnabled() {
+		dnsDialer, err := proxyMgr.GetDNSDialer()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize DNS proxy dialer: %v", err)
+		}
+		if dnsDialer == nil && cfg.StrictDNS {
+			return nil, fmt.Errorf("strict DNS: no active proxy available")
+		}
+		dnsResolver.SetProxyDialer(dnsDialer, cfg.StrictDNS)
+	} else if cfg.StrictDNS {
+		return nil, fmt.Errorf("strict DNS requires --tor or --proxy")
+	}
+
+	if err := dnsResolver.SetRecordTypes(cfg.RecordTypes); err != nil {
+		return nil, fmt.Errorf("invalid record types: %v", err)
+	}
+
+	if cfg.TakeoverData != "" {
+		if err := dnsResolver.LoadTakeoverFingerprints(cfg.TakeoverData); err != nil {
+			return nil, fmt.Errorf("failed to load takeover fingerprints: %v", err)
+		}
+	}
+
+	if cfg.TrustAnchor != "" {
+		if err := dnsResolver.LoadTrustAnchor(cfg.TrustAnchor); err != nil {
+			return nil, fmt.Errorf("failed to load trust anchor: %v", err)
+		}
+	}
 
 	// Initialize fingerprint spoofer
 	scanner.fingerprint = utils.NewFingerprintSpoofer(logger)
+	scanner.fingerprint.SetRandomFingerprint(cfg.RandomTLS)
+	// Enumerasi sudah menguji semua versi, probe legacy hanya untuk scan tanpa --tls-enum
+	scanner.fingerprint.SetLegacyProbe(cfg.TLSLegacy && !cfg.TLSEnum)
+	scanner.fingerprint.SetSessionProbe(cfg.TLSSession)
+	if cfg.CABundle != "" {
+		if err := scanner.fingerprint.LoadCABundle(cfg.CABundle); err != nil {
+			return nil, fmt.Errorf("failed to load CA bundle: %v", err)
+		}
+	}
+
+	// Load wordlist untuk subdomain enumeration
+	if cfg.IsEnumEnabled() {
+		words, err := utils.LoadWordlist(cfg.Wordlist)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load wordlist: %v", err)
+		}
+		scanner.wordlist = words
+		logger.Info(fmt.Sprintf("📖 Wordlist loaded: %d kata (depth %d)", len(words), cfg.EnumDepth))
+	}
 
 	// Initialize grid scanner
 	scanner.grid = NewGrid(cfg, logger)
@@ -79,9 +176,26 @@ func (s *Scanner) ScanTargets(targets []string) ([]*ScanResult, error) {
 	// Channel untuk limit concurrent scanning
 	semaphore := make(chan struct{}, s.config.MaxThreads)
 
-	for i, target := range targets {
+	// Queue dinamis: target hasil enumerasi ikut masuk ke antrian scan
+	seen := make(map[string]bool)
+	var order []string
+	queued, started := 0, 0
+
+	var submit func(tgt, parent string, depth int)
+	submit = func(tgt, parent string, depth int) {
+		mutex.Lock()
+		key := strings.ToLower(tgt)
+		if seen[key] {
+			mutex.Unlock()
+			return
+		}
+		seen[key] = true
+		order = append(order, tgt)
+		queued++
+		mutex.Unlock()
+
 		wg.Add(1)
-		go func(idx int, tgt string) {
+		go func() {
 			defer wg.Done()
 
 			// Acquire semaphore
@@ -91,23 +205,229 @@ func (s *Scanner) ScanTargets(targets []string) ([]*ScanResult, error) {
 			// Random delay untuk stealth
 			s.randomDelay()
 
+			mutex.Lock()
+			started++
+			current, total := started, queued
+			mutex.Unlock()
+
 			// Scan single target
-			result := s.scanSingleTarget(tgt, idx+1, len(targets))
+			result := s.scanSingleTarget(tgt, current, total)
+			result.DiscoveredFrom = parent
+			result.Depth = depth
+
+			// Target baru dari enumerasi dan zone transfer
+			for _, host := range s.discoverTargets(tgt, depth, result) {
+				submit(host, tgt, depth+1)
+			}
 
 			// Add to results
 			mutex.Lock()
 			results = append(results, result)
 			mutex.Unlock()
+		}()
+	}
 
-		}(i, target)
+	// Deteksi wildcard per parent zone sebelum scanning
+	s.prepareWildcards(targets)
+
+	s.sanScope = make(map[string]bool)
+	for _, target := range targets {
+		if registrable := utils.RegistrableDomain(target); registrable != "" {
+			s.sanScope[registrable] = true
+		}
+	}
+
+	for _, target := range targets {
+		submit(target, "", 0)
 	}
 
 	wg.Wait()
 	s.logger.Info("✅ Semua target selesai di-scan")
 
+	// Target yang hanya cocok dengan wildcard DNS dibuang dari grid, statistik dan output
+	if s.config.NoWildcard {
+		var hidden int
+		order, results, hidden = excludeWildcard(order, results)
+		if hidden > 0 {
+			s.logger.Info(fmt.Sprintf("🃏 %d target wildcard disembunyikan", hidden))
+		}
+	}
+
+	s.grid.DisplayGridProgress(order, results)
+
 	return results, nil
 }
 
+// excludeWildcard membuang target yang hasilnya hanya cocok dengan wildcard DNS
+func excludeWildcard(targets []string, results []*ScanResult) ([]string, []*ScanResult, int) {
+	wildcard := make(map[string]bool)
+	filteredResults := make([]*ScanResult, 0, len(results))
+
+	for _, result := range results {
+		if result.Wildcard {
+			wildcard[result.Target] = true
+			continue
+		}
+		filteredResults = append(filteredResults, result)
+	}
+
+	filteredTargets := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if !wildcard[target] {
+			filteredTargets = append(filteredTargets, target)
+		}
+	}
+
+	return filteredTargets, filteredResults, len(wildcard)
+}
+
+// prepareWildcards mendeteksi wildcard DNS untuk setiap parent zone target secara concurrent
+func (s *Scanner) prepareWildcards(targets []string) {
+	zones := make(map[string]bool)
+	for _, target := range targets {
+		if !utils.ValidateDomain(target) || utils.ValidateIP(target) {
+			continue
+		}
+		// Zone di atas registrable domain (TLD) tidak di-probe
+		if zone := utils.WildcardZone(target); zone != "." {
+			zones[zone] = true
+		}
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, s.config.MaxThreads)
+
+	for zone := range zones {
+		wg.Add(1)
+		go func(z string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			s.wildcardFor(z)
+		}(zone)
+	}
+
+	wg.Wait()
+}
+
+// wildcardFor mengembalikan wildcard signature zone (nil jika tidak ada), probe jika belum di-cache
+func (s *Scanner) wildcardFor(zone string) *utils.WildcardSignature {
+	zone = strings.ToLower(zone)
+	if zone == "." {
+		return nil
+	}
+
+	s.wildcardMutex.Lock()
+	signature, ok := s.wildcards[zone]
+	s.wildcardMutex.Unlock()
+	if ok {
+		return signature
+	}
+
+	signature = s.dnsResolver.DetectWildcard(zone)
+	s.storeWildcard(zone, signature)
+
+	if signature != nil {
+		s.logger.Info(fmt.Sprintf("🃏 Wildcard DNS terdeteksi di *.%s", zone))
+	}
+	return signature
+}
+
+// storeWildcard menyimpan wildcard signature zone ke cache
+func (s *Scanner) storeWildcard(zone string, signature *utils.WildcardSignature) {
+	s.wildcardMutex.Lock()
+	s.wildcards[strings.ToLower(zone)] = signature
+	s.wildcardMutex.Unlock()
+}
+
+// discoverTargets mengumpulkan target baru dari subdomain enumeration dan zone transfer.
+// Zone transfer tetap dicek di setiap depth (sekali per apex zone), tetapi host hasilnya
+// hanya di-scan jika masih di bawah --enum-depth dan dibatasi --axfr-max-hosts
+func (s *Scanner) discoverTargets(target string, depth int, result *ScanResult) []string {
+	var discovered []string
+
+	if s.config.CheckAXFR && utils.ValidateDomain(target) && !utils.ValidateIP(target) {
+		if transfer := s.transferZone(target); transfer != nil {
+			result.ZoneTransfer = transfer
+			if depth < s.config.EnumDepth {
+				discovered = append(discovered, s.limitTransferHosts(transfer)...)
+			}
+		}
+	}
+
+	if depth >= s.config.EnumDepth {
+		return discovered
+	}
+
+	// SAN sertifikat, juga untuk target IP (site lain di IP yang sama). Sertifikat tanpa SNI
+	// atau SNI palsu bisa milik pihak lain, jadi hanya SAN di registrable domain input yang diikuti
+	if s.config.FollowSANs {
+		for _, name := range result.SANCandidates {
+			if s.sanScope[utils.RegistrableDomain(name)] {
+				discovered = append(discovered, name)
+			} else {
+				s.logger.Debug(fmt.Sprintf("SAN %s di luar scope target input, tidak di-scan", name))
+			}
+		}
+	}
+
+	if utils.ValidateIP(target) || !utils.ValidateDomain(target) {
+		return discovered
+	}
+
+	// Subdomain enumeration dari wordlist
+	if s.config.IsEnumEnabled() && len(s.wordlist) > 0 {
+		enum := s.dnsResolver.EnumerateSubdomains(target, s.wordlist, s.config.MaxThreads)
+		result.Subdomains = enum
+		s.storeWildcard(target, enum.Wildcard)
+		for _, hit := range enum.Hits {
+			discovered = append(discovered, hit.Name)
+		}
+	}
+
+	return discovered
+}
+
+// limitTransferHosts membatasi host hasil zone transfer yang di-scan sesuai
+// --axfr-max-hosts; semua host tetap tercatat di hasil zone transfer
+func (s *Scanner) limitTransferHosts(transfer *utils.ZoneTransferResult) []string {
+	hosts := transfer.Hosts
+	if limit := s.config.AXFRMaxHosts; limit > 0 && len(hosts) > limit {
+		s.logger.Warn(fmt.Sprintf("Zone transfer %s menghasilkan %d host, hanya %d yang di-scan (--axfr-max-hosts)",
+			transfer.Domain, len(hosts), limit))
+		hosts = hosts[:limit]
+	}
+	return hosts
+}
+
+// transferZone mencoba zone transfer untuk apex zone target, sekali per zone.
+// Mengembalikan nil jika zone sudah dicoba oleh target lain atau tidak punya NS
+func (s *Scanner) transferZone(target string) *utils.ZoneTransferResult {
+	apex, err := s.dnsResolver.ZoneApex(target)
+	if err != nil {
+		s.logger.Debug(fmt.Sprintf("Zone transfer %s dilewati: %v", target, err))
+		return nil
+	}
+
+	s.axfrMutex.Lock()
+	done := s.axfrZones[apex]
+	s.axfrZones[apex] = true
+	s.axfrMutex.Unlock()
+	if done {
+		return nil
+	}
+
+	nameservers, err := s.dnsResolver.LookupNS(apex)
+	if err != nil || len(nameservers) == 0 {
+		s.logger.Debug(fmt.Sprintf("Zone transfer %s dilewati: NS tidak ditemukan", apex))
+		return nil
+	}
+
+	return s.dnsResolver.CheckZoneTransfer(apex, nameservers)
+}
+
 // scanSingleTarget melakukan scanning untuk satu target
 func (s *Scanner) scanSingleTarget(target string, current, total int) *ScanResult {
 	startTime := time.Now()
@@ -125,18 +445,66 @@ func (s *Scanner) scanSingleTarget(target string, current, total int) *ScanResul
 	ctx, cancel := context.WithTimeout(context.Background(), s.config.GetTimeout())
 	defer cancel()
 
-	// DNS Resolution
-	if dnsRecords, err := s.dnsResolver.ResolveAll(target); err == nil {
+	// DNS Resolution (query dibatasi deadline scan target)
+	dnsStart := time.Now()
+	resolver := s.dnsResolver.WithContext(ctx)
+	dnsRecords, dnsErrors, err := resolver.ResolveAllWithErrors(target)
+	if err == nil {
 		result.DNSRecords = dnsRecords
-		if ips, ok := dnsRecords["A"]; ok && len(ips) > 0 {
-			result.IP = ips[0]
-		}
 	} else {
 		s.logger.Debug(fmt.Sprintf("DNS resolution failed for %s: %v", target, err))
 	}
+	result.DNSErrors = dnsErrors
+
+	// Alamat untuk port scan/TLS selalu di-resolve, walaupun A/AAAA tidak ada di --records
+	var addresses []string
+	if utils.ValidateIP(target) {
+		// Target IP langsung dipakai untuk port scan, TLS dan kandidat SAN
+		result.IP = target
+	} else {
+		addresses = s.resolveAddresses(resolver, target, dnsRecords)
+		if len(addresses) > 0 {
+			result.IP = addresses[0]
+		}
+	}
+
+	// Jawaban identik dengan wildcard zone berarti target belum tentu benar-benar ada
+	if len(addresses) > 0 || len(dnsRecords["CNAME"]) > 0 {
+		if signature := s.wildcardFor(utils.WildcardZone(target)); signature.Matches(addresses, dnsRecords["CNAME"]) {
+			result.Wildcard = true
+		}
+	}
+
+	// Cross-resolver comparison
+	if s.config.CompareDNS && utils.ValidateDomain(target) && !utils.ValidateIP(target) {
+		for _, recordType := range []string{"A", "AAAA"} {
+			if comparison, err := s.dnsResolver.CompareResolvers(target, recordType); err == nil {
+				result.ResolverComparison = append(result.ResolverComparison, comparison)
+			}
+		}
+	}
+
+	// NS delegation audit (hanya untuk zone apex yang punya NS records)
+	if s.config.AuditNS && len(result.DNSRecords["NS"]) > 0 && !utils.ValidateIP(target) {
+		result.Delegation = s.dnsResolver.AuditDelegation(target, result.DNSRecords["NS"])
+	}
+
+	// DNSSEC validation
+	if s.config.CheckDNSSEC && utils.ValidateDomain(target) && !utils.ValidateIP(target) {
+		result.DNSSEC = s.dnsResolver.ValidateDNSSEC(target)
+	}
+
+	// Email security posture
+	if s.config.CheckEmail && utils.ValidateDomain(target) && !utils.ValidateIP(target) {
+		result.EmailSecurity = s.dnsResolver.AnalyzeEmailSecurity(target, result.DNSRecords, s.config.DKIMSelectors)
+	}
+
+	result.DNSTime = time.Since(dnsStart)
 
 	// Port Scanning
-	if result.IP != "" {
+	if result.IP == "" {
+		s.logger.Warn(fmt.Sprintf("Tidak ada alamat A/AAAA untuk %s, port scan dan TLS dilewati", target))
+	} else {
 		openPorts, services := s.scanPorts(ctx, result.IP)
 		result.OpenPorts = openPorts
 		result.Services = services
@@ -149,25 +517,80 @@ func (s *Scanner) scanSingleTarget(target string, current, total int) *ScanResul
 		}
 	}
 
+	// CNAME chain untuk CDN detection dan takeover check
+	var cnameChain *utils.CNAMEChain
+	if utils.ValidateDomain(target) && !utils.ValidateIP(target) {
+		if s.config.CheckTakeover {
+			result.Takeover = s.dnsResolver.CheckTakeover(target)
+			cnameChain = &result.Takeover.CNAMEChain
+		} else {
+			cnameChain = s.dnsResolver.FollowCNAMEChain(target)
+		}
+	}
+
 	// CDN Detection
-	if cdnInfo := s.detectCDN(target); cdnInfo != nil {
+	if cdnInfo := s.detectCDN(cnameChain); cdnInfo != nil {
 		result.CDNInfo = cdnInfo
 	}
 
-	// TLS Fingerprinting
-	if tlsInfo := s.performTLSFingerprinting(target); tlsInfo != nil {
-		result.TLSInfo = tlsInfo
+	// TLS Fingerprinting di setiap port terbuka yang berbicara TLS
+	if tlsPorts := s.performTLSFingerprinting(target, result.IP, result.OpenPorts); len(tlsPorts) > 0 {
+		result.TLS = tlsPorts
+		result.TLSInfo = legacyTLSInfo(tlsPorts)
 	}
 
+	// Kandidat target baru dari SAN sertifikat
+	result.SANCandidates = collectSANCandidates(result.TLS)
+
+	// STARTTLS untuk port plaintext yang terbuka
+	if starttls := s.performSTARTTLS(target, result.IP, result.OpenPorts); len(starttls) > 0 {
+		result.STARTTLS = starttls
+	}
+
+	// Enumerasi versi protokol dan cipher suite
+	if s.config.TLSEnum && len(result.TLS) > 0 {
+		result.TLSEnum = make(map[string]*utils.TLSEnumResult)
+		for address := range result.TLS {
+			result.TLSEnum[address] = s.fingerprint.EnumerateTLSAddress(address, target)
+		}
+	}
+
+	// Fingerprint JARM aktif untuk clustering implementasi TLS
+	if s.config.JARM && len(result.TLS) > 0 {
+		result.JARM = make(map[string]*utils.JARMResult)
+		for address := range result.TLS {
+			result.JARM[address] = s.fingerprint.JARMAddress(address, target)
+		}
+	}
+
+	// Rule miskonfigurasi TLS dari handshake, enumerasi dan STARTTLS
+	result.TLSFindings = assessTLSResult(result)
+
 	result.ScanTime = time.Since(startTime)
 
-	if !s.config.Silent {
+	if !s.config.Silent && !(s.config.NoWildcard && result.Wildcard) {
 		s.displayScanResult(result)
 	}
 
 	return result
 }
 
+// resolveAddresses mengembalikan alamat A lalu AAAA target, memakai hasil ResolveAll jika
+// record type tersebut sudah di-query dan lookup terpisah jika tidak
+func (s *Scanner) resolveAddresses(resolver *utils.DNSResolver, target string, records map[string][]string) []string {
+	var addresses []string
+
+	for _, recordType := range []string{"A", "AAAA"} {
+		ips, ok := records[recordType]
+		if !ok && !s.dnsResolver.HasRecordType(recordType) {
+			ips, _ = resolver.LookupType(target, recordType)
+		}
+		addresses = append(addresses, ips...)
+	}
+
+	return addresses
+}
+
 // randomDelay menerapkan delay random untuk stealth
 func (s *Scanner) randomDelay() {
 	minDelay, maxDelay, _ := s.config.GetDelayRange()
@@ -181,7 +604,12 @@ func (s *Scanner) randomDelay() {
 
 // scanPorts melakukan port scanning
 func (s *Scanner) scanPorts(ctx context.Context, ip string) ([]int, map[int]string) {
-	commonPorts := []int{21, 22, 23, 25, 53, 80, 110, 443, 993, 995, 8080, 8443}
+	commonPorts := []int{21, 22, 23, 25, 53, 80, 110, 143, 389, 443, 587, 993, 995, 5222, 5432, 8080, 8443}
+	for _, port := range s.config.Ports {
+		if !containsPort(commonPorts, port) {
+			commonPorts = append(commonPorts, port)
+		}
+	}
 	var openPorts []int
 	services := make(map[int]string)
 
@@ -197,7 +625,7 @@ func (s *Scanner) scanPorts(ctx context.Context, ip string) ([]int, map[int]stri
 
 // isPortOpen mengecek apakah port terbuka
 func (s *Scanner) isPortOpen(ctx context.Context, ip string, port int) bool {
-	address := fmt.Sprintf("%s:%d", ip, port)
+	address := net.JoinHostPort(ip, strconv.Itoa(port))
 	
 	conn, err := net.DialTimeout("tcp", address, 3*time.Second)
 	if err != nil {
@@ -218,9 +646,14 @@ func (s *Scanner) identifyService(port int) string {
 		53:   "dns",
 		80:   "http",
 		110:  "pop3",
+		143:  "imap",
+		389:  "ldap",
 		443:  "https",
+		587:  "submission",
 		993:  "imaps",
 		995:  "pop3s",
+		5222: "xmpp-client",
+		5432: "postgresql",
 		8080: "http-alt",
 		8443: "https-alt",
 	}
@@ -244,18 +677,20 @@ func (s *Scanner) performTraceroute(ctx context.Context, ip string) ([]string, e
 	return hops, nil
 }
 
-// detectCDN mendeteksi penggunaan CDN
-func (s *Scanner) detectCDN(target string) map[string]interface{} {
+// detectCDN mendeteksi penggunaan CDN dari setiap hop CNAME chain
+func (s *Scanner) detectCDN(chain *utils.CNAMEChain) map[string]interface{} {
+	if chain == nil {
+		return nil
+	}
+
 	cdnInfo := make(map[string]interface{})
 	
 	// DNS-based CDN detection
-	if cnames, err := s.dnsResolver.LookupCNAME(target); err == nil {
-		for _, cname := range cnames {
-			if s.isCDNDomain(cname) {
-				cdnInfo["provider"] = s.identifyCDNProvider(cname)
-				cdnInfo["cname"] = cname
-				break
-			}
+	for _, cname := range chain.Chain {
+		if s.isCDNDomain(cname) {
+			cdnInfo["provider"] = s.identifyCDNProvider(cname)
+			cdnInfo["cname"] = cname
+			break
 		}
 	}
 	
@@ -302,8 +737,84 @@ func (s *Scanner) identifyCDNProvider(domain string) string {
 }
 
 // performTLSFingerprinting melakukan TLS fingerprinting
-func (s *Scanner) performTLSFingerprinting(target string) map[string]interface{} {
-	return s.fingerprint.AnalyzeTLS(target)
+// di setiap port terbuka (kecuali port STARTTLS) dengan SNI dari hostname target.
+// Port yang handshake-nya gagal dianggap bukan TLS dan tidak dicatat
+func (s *Scanner) performTLSFingerprinting(target, ip string, openPorts []int) map[string]*utils.TLSFingerprint {
+	results := make(map[string]*utils.TLSFingerprint)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, port := range openPorts {
+		if _, plaintext := utils.STARTTLSPorts[port]; plaintext {
+			continue
+		}
+
+		wg.Add(1)
+		go func(port int) {
+			defer wg.Done()
+
+			address := net.JoinHostPort(ip, strconv.Itoa(port))
+			fingerprint, err := s.fingerprint.AnalyzeTLSAddress(address, target, s.config.GetTimeout())
+			if err != nil {
+				s.logger.Debug(fmt.Sprintf("TLS handshake gagal untuk %s: %v", address, err))
+				return
+			}
+
+			// Perbandingan sertifikat dengan SNI benar, tanpa SNI dan SNI palsu
+			if s.config.SNICheck {
+				fingerprint.SNI = s.fingerprint.CompareSNI(address, target, s.config.GetTimeout())
+			}
+
+			mutex.Lock()
+			results[address] = fingerprint
+			mutex.Unlock()
+		}(port)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// collectSANCandidates menggabungkan kandidat SAN dari perbandingan SNI di semua port TLS
+func collectSANCandidates(tlsPorts map[string]*utils.TLSFingerprint) []string {
+	seen := make(map[string]bool)
+	var candidates []string
+
+	for _, fingerprint := range tlsPorts {
+		if fingerprint.SNI == nil {
+			continue
+		}
+		for _, name := range fingerprint.SNI.Candidates {
+			if !seen[name] {
+				seen[name] = true
+				candidates = append(candidates, name)
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+	return candidates
+}
+
+// performSTARTTLS menjalankan upgrade STARTTLS dan analisis TLS di setiap port plaintext yang terbuka
+func (s *Scanner) performSTARTTLS(target, ip string, openPorts []int) map[string]*utils.STARTTLSResult {
+	serverName := target
+	if utils.ValidateIP(target) {
+		serverName = ""
+	}
+
+	results := make(map[string]*utils.STARTTLSResult)
+	for _, port := range openPorts {
+		protocol, ok := utils.STARTTLSPorts[port]
+		if !ok {
+			continue
+		}
+
+		address := net.JoinHostPort(ip, strconv.Itoa(port))
+		results[address] = s.fingerprint.AnalyzeSTARTTLS(address, serverName, protocol)
+	}
+
+	return results
 }
 
 // displayScanResult menampilkan hasil scan ke terminal
@@ -318,16 +829,242 @@ func (s *Scanner) displayScanResult(result *ScanResult) {
 		fmt.Printf("    🔓 Open Ports: %v\n", result.OpenPorts)
 	}
 
+	for _, recordType := range sortedDNSErrorTypes(result.DNSErrors) {
+		fmt.Printf("    ⚠️  DNS %s: %s\n", recordType, result.DNSErrors[recordType])
+	}
+
 	if result.CDNInfo != nil {
 		if provider, ok := result.CDNInfo["provider"]; ok {
 			fmt.Printf("    🌐 CDN: %s\n", provider)
 		}
 	}
 
-	fmt.Printf("    ⏱️  Scan Time: %v\n", result.ScanTime.Round(time.Millisecond))
+	if result.Wildcard {
+		fmt.Printf("    🃏 Wildcard: jawaban identik dengan *.%s\n", utils.WildcardZone(result.Target))
+	}
+
+	if result.Takeover != nil && result.Takeover.Vulnerable {
+		fmt.Printf("    🚨 Takeover: %s → %s (%s)\n", result.Target, result.Takeover.Final, result.Takeover.Service)
+	}
+
+	if result.Delegation != nil && len(result.Delegation.Findings) > 0 {
+		fmt.Printf("    ⚠️  Delegasi NS: %d findings\n", len(result.Delegation.Findings))
+	}
+
+	if len(result.STARTTLS) > 0 {
+		var upgrades []string
+		for address, starttls := range result.STARTTLS {
+			status := "❌"
+			if starttls.Fingerprint != nil {
+				status = "✅"
+			} else if starttls.Offered {
+				status = "⚠️"
+			}
+			upgrades = append(upgrades, fmt.Sprintf("%s/%s %s", address, starttls.Protocol, status))
+		}
+		sort.Strings(upgrades)
+		fmt.Printf("    📧 STARTTLS: %s\n", strings.Join(upgrades, ", "))
+	}
+
+	if len(result.TLS) > 0 {
+		var ports []string
+		for address, fingerprint := range result.TLS {
+			ports = append(ports, fmt.Sprintf("%s (%s)", address, fingerprint.TLSVersion))
+		}
+		sort.Strings(ports)
+		fmt.Printf("    🔐 TLS: %s\n", strings.Join(ports, ", "))
+	}
+
+	for _, address := range sortedTLSAddresses(result.TLS) {
+		fmt.Printf("    🔑 %s: %s\n", address, describeTLSSession(result.TLS[address], s.config.TLSSession))
+	}
+
+	for _, address := range sortedTLSAddresses(result.TLS) {
+		sni := result.TLS[address].SNI
+		if sni == nil {
+			continue
+		}
+		if sni.SNIRequired {
+			fmt.Printf("    🪪 %s: default vhost %s, %d nama tersembunyi\n", address, sni.DefaultVhost, len(sni.HiddenNames))
+		} else if sni.CatchAll {
+			fmt.Printf("    🪪 %s: target adalah default vhost (SNI apa pun diterima)\n", address)
+		}
+	}
+	if len(result.SANCandidates) > 0 {
+		fmt.Printf("    🧭 Kandidat SAN: %d (%s)\n", len(result.SANCandidates), strings.Join(result.SANCandidates, ", "))
+	}
+
+	var enumAddresses []string
+	for address := range result.TLSEnum {
+		enumAddresses = append(enumAddresses, address)
+	}
+	sort.Strings(enumAddresses)
+
+	for _, address := range enumAddresses {
+		enum := result.TLSEnum[address]
+		if enum.Error != "" {
+			continue
+		}
+		var versions []string
+		for _, protocol := range enum.Protocols {
+			if protocol.Supported {
+				versions = append(versions, protocol.Version)
+			}
+		}
+		fmt.Printf("    🧮 %s: %s (%d findings)\n", address, strings.Join(versions, ", "), len(enum.Findings))
+	}
+
+	for _, address := range sortedTLSAddresses(result.TLS) {
+		if jarm, ok := result.JARM[address]; ok && jarm.Error == "" {
+			fmt.Printf("    🧬 %s: JARM %s\n", address, jarm.Fingerprint)
+		}
+	}
+
+	if len(result.TLSFindings) > 0 {
+		fmt.Printf("    🚨 TLS: %d findings (tertinggi: %s)\n", len(result.TLSFindings), utils.HighestSeverity(result.TLSFindings))
+		for _, finding := range result.TLSFindings {
+			symbol := severitySymbol(finding.Severity)
+			if symbol == "" {
+				symbol = "⚪"
+			}
+			fmt.Printf("       %s [%s] %s - %s\n", symbol, strings.ToUpper(finding.Severity), finding.Title, finding.Detail)
+		}
+	}
+
+	fmt.Printf("    ⏱️  Scan Time: %v (DNS: %v)\n", result.ScanTime.Round(time.Millisecond), result.DNSTime.Round(time.Millisecond))
 	fmt.Println()
 }
 
+// assessTLSResult menjalankan rule miskonfigurasi TLS di setiap port TLS dan STARTTLS
+func assessTLSResult(result *ScanResult) []utils.Finding {
+	var findings []utils.Finding
+
+	for _, address := range sortedTLSAddresses(result.TLS) {
+		findings = append(findings, utils.AssessTLS(address, result.TLS[address], result.TLSEnum[address])...)
+	}
+
+	addresses := make([]string, 0, len(result.STARTTLS))
+	for address := range result.STARTTLS {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	for _, address := range addresses {
+		starttls := result.STARTTLS[address]
+		if starttls.Fingerprint == nil {
+			continue
+		}
+		findings = append(findings, utils.AssessTLS(address+"/"+starttls.Protocol, starttls.Fingerprint, nil)...)
+	}
+
+	return findings
+}
+
+// describeTLSSession meringkas ALPN, key exchange, OCSP, resumption, 0-RTT dan HSTS.
+// Tanpa --tls-session resumption tidak diuji, jadi hanya ticket yang terlihat yang ditampilkan
+func describeTLSSession(fingerprint *utils.TLSFingerprint, sessionProbe bool) string {
+	var details []string
+
+	if fingerprint.ALPN != "" {
+		details = append(details, "ALPN "+fingerprint.ALPN)
+	}
+	if fingerprint.KeyExchange != "" {
+		keyExchange := fingerprint.KeyExchange
+		if fingerprint.KeyExchangeGroup != "" {
+			keyExchange += " " + fingerprint.KeyExchangeGroup
+		}
+		details = append(details, keyExchange)
+	}
+
+	switch {
+	case fingerprint.OCSP != nil && fingerprint.OCSP.CertStatus != "":
+		details = append(details, "OCSP "+fingerprint.OCSP.CertStatus)
+	case fingerprint.OCSPStapled:
+		details = append(details, "OCSP stapled")
+	default:
+		details = append(details, "tanpa OCSP staple")
+	}
+
+	switch {
+	case fingerprint.Resumed:
+		details = append(details, "resumption ✅")
+	case fingerprint.SessionTicket && !sessionProbe:
+		details = append(details, "session ticket")
+	case fingerprint.SessionTicket:
+		details = append(details, "ticket tanpa resumption")
+	case sessionProbe:
+		details = append(details, "tanpa session ticket")
+	}
+
+	if fingerprint.EarlyDataOffered != nil && *fingerprint.EarlyDataOffered {
+		details = append(details, fmt.Sprintf("0-RTT diiklankan (%d byte)", fingerprint.MaxEarlyData))
+	}
+
+	if fingerprint.HSTS != nil {
+		if fingerprint.HSTS.Present {
+			details = append(details, fmt.Sprintf("HSTS max-age=%d", fingerprint.HSTS.MaxAge))
+		} else if fingerprint.HSTS.Error == "" {
+			details = append(details, "tanpa HSTS")
+		}
+	}
+
+	return strings.Join(details, ", ")
+}
+
+// sortedDNSErrorTypes mengembalikan record type yang lookup-nya error secara berurutan
+func sortedDNSErrorTypes(errs map[string]string) []string {
+	types := make([]string, 0, len(errs))
+	for recordType := range errs {
+		types = append(types, recordType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// sortedTLSAddresses mengembalikan IP:port hasil TLS yang diurutkan
+func sortedTLSAddresses(tlsPorts map[string]*utils.TLSFingerprint) []string {
+	addresses := make([]string, 0, len(tlsPorts))
+	for address := range tlsPorts {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// legacyTLSInfo membentuk field tls_info lama dari port TLS utama (443 jika terbuka,
+// selain itu address terkecil) agar consumer JSON lama tetap berjalan
+func legacyTLSInfo(tlsPorts map[string]*utils.TLSFingerprint) map[string]interface{} {
+	addresses := sortedTLSAddresses(tlsPorts)
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	primary := addresses[0]
+	for _, address := range addresses {
+		if _, port, err := net.SplitHostPort(address); err == nil && port == "443" {
+			primary = address
+			break
+		}
+	}
+
+	fingerprint := tlsPorts[primary]
+	return map[string]interface{}{
+		"fingerprint":             fingerprint,
+		"handshake_complete":      true,
+		"peer_certificates_count": len(fingerprint.Chain),
+	}
+}
+
+// containsPort mengecek apakah port ada di daftar
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
 // Helper function
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || 
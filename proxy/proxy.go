@@ -114,7 +114,7 @@ func (m *Manager) GetDialer() (proxy.Dialer, error) {
 	proxyConfig := m.getNextProxy()
 	
 	switch proxyConfig.Type {
-	case "socks5":
+	case "socks5", "socks5h":
 		return m.createSOCKS5Dialer(proxyConfig)
 	case "http", "https":
 		return m.createHTTPDialer(proxyConfig)
@@ -123,6 +123,42 @@ func (m *Manager) GetDialer() (proxy.Dialer, error) {
 	}
 }
 
+// GetDNSDialer mendapatkan dialer tetap (tanpa rotasi) untuk query DNS.
+// Proxy SOCKS5/TOR diutamakan karena hostname di-resolve di sisi proxy,
+// mengembalikan nil jika tidak ada proxy aktif
+func (m *Manager) GetDNSDialer() (proxy.Dialer, error) {
+	var selected *ProxyConfig
+	for i := range m.proxies {
+		candidate := &m.proxies[i]
+		if !candidate.Active {
+			continue
+		}
+		if selected == nil || (isSOCKS5(candidate.Type) && !isSOCKS5(selected.Type)) {
+			selected = candidate
+		}
+	}
+
+	if selected == nil {
+		return nil, nil
+	}
+
+	m.logger.Debug(fmt.Sprintf("DNS proxy: %s (%s)", selected.Address, selected.Type))
+
+	switch selected.Type {
+	case "socks5", "socks5h":
+		return m.createSOCKS5Dialer(*selected)
+	case "http", "https":
+		return m.createHTTPDialer(*selected)
+	default:
+		return nil, fmt.Errorf("unsupported proxy type: %s", selected.Type)
+	}
+}
+
+// isSOCKS5 mengecek apakah tipe proxy adalah SOCKS5
+func isSOCKS5(proxyType string) bool {
+	return proxyType == "socks5" || proxyType == "socks5h"
+}
+
 // getNextProxy mendapatkan proxy berikutnya untuk rotasi
 func (m *Manager) getNextProxy() ProxyConfig {
 	if len(m.proxies) == 0 {
@@ -241,7 +277,7 @@ func (m *Manager) GetHTTPClient() (*http.Client, error) {
 			}).DialContext,
 		}
 
-	case "socks5":
+	case "socks5", "socks5h":
 		dialer, err := m.createSOCKS5Dialer(proxyConfig)
 		if err != nil {
 			return nil, err
@@ -1,37 +1,76 @@
 package utils
 
 import (
+        "context"
+        "errors"
         "fmt"
         "net"
+        "net/http"
         "strings"
+        "sync"
         "time"
 
         "github.com/miekg/dns"
+        "golang.org/x/net/proxy"
 )
 
 // DNSResolver mengelola DNS resolution dengan support DoH
 type DNSResolver struct {
-        client    *dns.Client
-        servers   []string
-        useDoH    bool
-        logger    *Logger
+        client       *dns.Client
+        tcpClient    *dns.Client
+        ctx          context.Context
+        ednsSize     uint16
+        servers      []string
+        useDoH       bool
+        logger       *Logger
+        trustAnchors []*dns.DS
+        httpClient   *http.Client
+        recordTypes  []string
+        limiter      *RateLimiter
+        dialer       proxy.Dialer
+        strictProxy  bool
+        fallbackWarn *sync.Once
+
+        takeoverFingerprints []TakeoverFingerprint
 }
 
 // DNSRecord menyimpan record DNS
 type DNSRecord struct {
+        Name  string `json:"name,omitempty"`
         Type  string `json:"type"`
         Value string `json:"value"`
         TTL   uint32 `json:"ttl"`
 }
 
+// DefaultEDNSBufferSize adalah ukuran buffer EDNS0 default (rekomendasi DNS Flag Day 2020)
+const DefaultEDNSBufferSize = 1232
+
+// servfailRetries dan servfailBackoff mengatur retry ke server yang sama saat SERVFAIL
+const (
+        servfailRetries = 2
+        servfailBackoff = 250 * time.Millisecond
+)
+
+// resolverStagger adalah jeda sebelum server berikutnya ikut di-race
+const resolverStagger = 300 * time.Millisecond
+
 // NewDNSResolver membuat instance DNSResolver baru
 func NewDNSResolver(useDoH bool, logger *Logger) (*DNSResolver, error) {
         resolver := &DNSResolver{
                 client: &dns.Client{
                         Timeout: 10 * time.Second,
                 },
-                useDoH: useDoH,
+                tcpClient: &dns.Client{
+                        Net:     "tcp",
+                        Timeout: 10 * time.Second,
+                },
+                ctx:      context.Background(),
+                ednsSize: DefaultEDNSBufferSize,
+                useDoH:   useDoH,
                 logger: logger,
+                httpClient: &http.Client{
+                        Timeout: 10 * time.Second,
+                },
         }
 
         // Setup DNS servers
@@ -54,45 +93,216 @@ func NewDNSResolver(useDoH bool, logger *Logger) (*DNSResolver, error) {
         return resolver, nil
 }
 
-// ResolveAll melakukan resolve semua jenis DNS record
-func (d *DNSResolver) ResolveAll(domain string) (map[string][]string, error) {
-        results := make(map[string][]string)
+// DefaultRecordTypes adalah record types yang di-query ResolveAll secara default
+var DefaultRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT"}
+
+// SupportedRecordTypes adalah semua record types yang dapat dipilih dari CLI
+var SupportedRecordTypes = []string{
+        "A", "AAAA", "CNAME", "MX", "NS", "TXT",
+        "SOA", "CAA", "SRV", "HTTPS", "SVCB", "DS", "TLSA",
+}
+
+// commonSRVServices adalah service SRV umum untuk discovery
+var commonSRVServices = []string{
+        "_sip._tcp", "_sip._udp", "_sips._tcp", "_sipfederationtls._tcp",
+        "_ldap._tcp", "_ldaps._tcp", "_kerberos._tcp", "_kerberos._udp", "_gc._tcp",
+        "_xmpp-client._tcp", "_xmpp-server._tcp",
+        "_submission._tcp", "_imap._tcp", "_imaps._tcp", "_pop3s._tcp",
+        "_caldav._tcp", "_caldavs._tcp", "_carddav._tcp", "_carddavs._tcp",
+        "_autodiscover._tcp", "_matrix._tcp", "_h323cs._tcp",
+}
+
+// tlsaPorts adalah port yang dicek untuk TLSA/DANE
+var tlsaPorts = []string{"_443._tcp", "_25._tcp", "_465._tcp", "_587._tcp"}
+
+// SetRecordTypes mengatur record types yang di-query oleh ResolveAll
+func (d *DNSResolver) SetRecordTypes(types []string) error {
+        var selected []string
+
+        for _, recordType := range types {
+                recordType = strings.ToUpper(strings.TrimSpace(recordType))
+                if recordType == "" {
+                        continue
+                }
+
+                if recordType == "ALL" {
+                        selected = append([]string{}, SupportedRecordTypes...)
+                        break
+                }
 
-        // A records
-        if aRecords, err := d.LookupA(domain); err == nil && len(aRecords) > 0 {
-                results["A"] = aRecords
+                supported := false
+                for _, item := range SupportedRecordTypes {
+                        if item == recordType {
+                                supported = true
+                                break
+                        }
+                }
+                if !supported {
+                        return fmt.Errorf("unsupported record type: %s", recordType)
+                }
+
+                selected = append(selected, recordType)
         }
 
-        // AAAA records (IPv6)
-        if aaaaRecords, err := d.LookupAAAA(domain); err == nil && len(aaaaRecords) > 0 {
-                results["AAAA"] = aaaaRecords
+        if len(selected) > 0 {
+                d.recordTypes = selected
         }
+        return nil
+}
 
-        // CNAME records
-        if cnameRecords, err := d.LookupCNAME(domain); err == nil && len(cnameRecords) > 0 {
-                results["CNAME"] = cnameRecords
+// HasRecordType mengecek apakah record type ikut di-query oleh ResolveAll
+func (d *DNSResolver) HasRecordType(recordType string) bool {
+        recordTypes := d.recordTypes
+        if len(recordTypes) == 0 {
+                recordTypes = DefaultRecordTypes
         }
 
-        // MX records
-        if mxRecords, err := d.LookupMX(domain); err == nil && len(mxRecords) > 0 {
-                results["MX"] = mxRecords
+        for _, item := range recordTypes {
+                if item == recordType {
+                        return true
+                }
         }
+        return false
+}
+
+// SetServers mengganti daftar resolver (port default 53)
+func (d *DNSResolver) SetServers(servers []string) {
+        var normalized []string
+
+        for _, server := range servers {
+                server = strings.TrimSpace(server)
+                if server == "" {
+                        continue
+                }
 
-        // NS records
-        if nsRecords, err := d.LookupNS(domain); err == nil && len(nsRecords) > 0 {
-                results["NS"] = nsRecords
+                if _, _, err := net.SplitHostPort(server); err != nil {
+                        server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+                }
+                normalized = append(normalized, server)
+        }
+
+        if len(normalized) > 0 {
+                d.servers = normalized
+                d.logger.Debug(fmt.Sprintf("DNS resolvers: %v", normalized))
+        }
+}
+
+// SetTimeout mengatur timeout per query DNS (UDP dan TCP)
+func (d *DNSResolver) SetTimeout(timeout time.Duration) {
+        if timeout <= 0 {
+                return
         }
+        d.client.Timeout = timeout
+        d.tcpClient.Timeout = timeout
+}
 
-        // TXT records
-        if txtRecords, err := d.LookupTXT(domain); err == nil && len(txtRecords) > 0 {
-                results["TXT"] = txtRecords
+// SetEDNSBufferSize mengatur ukuran buffer UDP yang diumumkan lewat EDNS0
+func (d *DNSResolver) SetEDNSBufferSize(size int) {
+        if size < dns.MinMsgSize || size > dns.MaxMsgSize {
+                d.logger.Warn(fmt.Sprintf("EDNS0 buffer %d tidak valid (%d-%d), tetap %d", size, dns.MinMsgSize, dns.MaxMsgSize, d.ednsSize))
+                return
         }
+        d.ednsSize = uint16(size)
+}
+
+// WithContext mengembalikan salinan resolver yang query-nya dibatasi ctx
+// (deadline ctx memotong timeout per query, cancel menghentikan retry)
+func (d *DNSResolver) WithContext(ctx context.Context) *DNSResolver {
+        clone := *d
+        clone.ctx = ctx
+        return &clone
+}
 
+// SetRateLimit membatasi jumlah query DNS per detik (0 = tanpa batas)
+func (d *DNSResolver) SetRateLimit(perSecond int) {
+        d.limiter = NewRateLimiter(perSecond)
+        if perSecond > 0 {
+                d.logger.Debug(fmt.Sprintf("DNS rate limit: %d query/detik", perSecond))
+        }
+}
+
+// ResolveAll melakukan resolve semua jenis DNS record yang dipilih secara concurrent
+func (d *DNSResolver) ResolveAll(domain string) (map[string][]string, error) {
+        results, _, err := d.ResolveAllWithErrors(domain)
+        return results, err
+}
+
+// ResolveAllWithErrors sama dengan ResolveAll tetapi juga mengembalikan error lookup per
+// record type (timeout, SERVFAIL, ...). NXDOMAIN tidak dianggap error
+func (d *DNSResolver) ResolveAllWithErrors(domain string) (map[string][]string, map[string]string, error) {
+        results := make(map[string][]string)
+        errs := make(map[string]string)
+
+        recordTypes := d.recordTypes
+        if len(recordTypes) == 0 {
+                recordTypes = DefaultRecordTypes
+        }
+
+        var mutex sync.Mutex
+        var wg sync.WaitGroup
+
+        for _, recordType := range recordTypes {
+                wg.Add(1)
+                go func(rtype string) {
+                        defer wg.Done()
+
+                        records, err := d.LookupType(domain, rtype)
+
+                        mutex.Lock()
+                        if len(records) > 0 {
+                                results[rtype] = records
+                        }
+                        if err != nil && !IsNXDomain(err) {
+                                errs[rtype] = err.Error()
+                        }
+                        mutex.Unlock()
+                }(recordType)
+        }
+
+        wg.Wait()
+
+        if len(errs) == 0 {
+                errs = nil
+        }
         if len(results) == 0 {
-                return nil, fmt.Errorf("no DNS records found for %s", domain)
+                return nil, errs, fmt.Errorf("no DNS records found for %s", domain)
         }
 
-        return results, nil
+        return results, errs, nil
+}
+
+// LookupType melakukan lookup berdasarkan nama record type
+func (d *DNSResolver) LookupType(domain, recordType string) ([]string, error) {
+        switch recordType {
+        case "A":
+                return d.LookupA(domain)
+        case "AAAA":
+                return d.LookupAAAA(domain)
+        case "CNAME":
+                return d.LookupCNAME(domain)
+        case "MX":
+                return d.LookupMX(domain)
+        case "NS":
+                return d.LookupNS(domain)
+        case "TXT":
+                return d.LookupTXT(domain)
+        case "SOA":
+                return d.LookupSOA(domain)
+        case "CAA":
+                return d.LookupCAA(domain)
+        case "SRV":
+                return d.LookupSRV(domain)
+        case "HTTPS":
+                return d.LookupHTTPS(domain)
+        case "SVCB":
+                return d.LookupSVCB(domain)
+        case "DS":
+                return d.LookupDS(domain)
+        case "TLSA":
+                return d.LookupTLSA(domain)
+        default:
+                return nil, fmt.Errorf("unsupported record type: %s", recordType)
+        }
 }
 
 // LookupA melakukan A record lookup
@@ -143,46 +353,282 @@ func (d *DNSResolver) LookupTXT(domain string) ([]string, error) {
         return d.lookupClassic(domain, dns.TypeTXT)
 }
 
-// lookupClassic melakukan DNS lookup dengan server tradisional
+// LookupSOA melakukan SOA record lookup (serial dan timers)
+func (d *DNSResolver) LookupSOA(domain string) ([]string, error) {
+        if d.useDoH {
+                return d.lookupDoH(domain, dns.TypeSOA)
+        }
+        return d.lookupClassic(domain, dns.TypeSOA)
+}
+
+// LookupCAA melakukan CAA record lookup
+func (d *DNSResolver) LookupCAA(domain string) ([]string, error) {
+        if d.useDoH {
+                return d.lookupDoH(domain, dns.TypeCAA)
+        }
+        return d.lookupClassic(domain, dns.TypeCAA)
+}
+
+// LookupHTTPS melakukan HTTPS record lookup (ALPN dan ECH hints)
+func (d *DNSResolver) LookupHTTPS(domain string) ([]string, error) {
+        if d.useDoH {
+                return d.lookupDoH(domain, dns.TypeHTTPS)
+        }
+        return d.lookupClassic(domain, dns.TypeHTTPS)
+}
+
+// LookupSVCB melakukan SVCB record lookup
+func (d *DNSResolver) LookupSVCB(domain string) ([]string, error) {
+        if d.useDoH {
+                return d.lookupDoH(domain, dns.TypeSVCB)
+        }
+        return d.lookupClassic(domain, dns.TypeSVCB)
+}
+
+// LookupDS melakukan DS record lookup
+func (d *DNSResolver) LookupDS(domain string) ([]string, error) {
+        if d.useDoH {
+                return d.lookupDoH(domain, dns.TypeDS)
+        }
+        return d.lookupClassic(domain, dns.TypeDS)
+}
+
+// LookupSRV melakukan SRV discovery untuk service umum di bawah domain
+func (d *DNSResolver) LookupSRV(domain string) ([]string, error) {
+        return d.lookupPrefixed(domain, commonSRVServices, dns.TypeSRV)
+}
+
+// LookupTLSA melakukan TLSA (DANE) lookup untuk port TLS umum
+func (d *DNSResolver) LookupTLSA(domain string) ([]string, error) {
+        return d.lookupPrefixed(domain, tlsaPorts, dns.TypeTLSA)
+}
+
+// lookupPrefixed melakukan lookup <prefix>.<domain> dan menandai hasil dengan prefix.
+// NXDOMAIN untuk prefix yang tidak ada adalah normal; error lain dikembalikan bersama
+// hasil yang berhasil
+func (d *DNSResolver) lookupPrefixed(domain string, prefixes []string, qtype uint16) ([]string, error) {
+        var results, failures []string
+
+        for _, prefix := range prefixes {
+                name := prefix + "." + domain
+
+                var records []string
+                var err error
+                if d.useDoH {
+                        records, err = d.lookupDoH(name, qtype)
+                } else {
+                        records, err = d.lookupClassic(name, qtype)
+                }
+                if err != nil && !IsNXDomain(err) {
+                        failures = append(failures, fmt.Sprintf("%s: %v", prefix, err))
+                }
+
+                for _, record := range records {
+                        results = append(results, prefix+" "+record)
+                }
+        }
+
+        if len(failures) > 0 {
+                return results, fmt.Errorf("%d/%d lookup %s gagal (%s)", len(failures), len(prefixes),
+                        dns.TypeToString[qtype], strings.Join(failures, "; "))
+        }
+        return results, nil
+}
+
+// lookupClassic melakukan DNS lookup dengan server tradisional. Server di-race
+// ala happy-eyeballs: server berikutnya ikut di-query jika server sebelumnya
+// gagal atau belum menjawab dalam resolverStagger. Jawaban pertama yang valid menang,
+// termasuk NOERROR tanpa record (NODATA) dan NXDOMAIN
 func (d *DNSResolver) lookupClassic(domain string, qtype uint16) ([]string, error) {
+        if len(d.servers) == 0 {
+                return nil, fmt.Errorf("no DNS servers configured")
+        }
+
+        ctx, cancel := context.WithCancel(d.ctx)
+        defer cancel()
+        racer := d.WithContext(ctx)
+
+        type answer struct {
+                results []string
+                err     error
+        }
+
+        answers := make(chan answer, len(d.servers))
+        launched, pending := 0, 0
+        var stagger <-chan time.Time
+
+        launch := func() {
+                server := d.servers[launched]
+                launched++
+                pending++
+
+                go func() {
+                        results, err := racer.queryClassic(server, domain, qtype)
+                        answers <- answer{results: results, err: err}
+                }()
+
+                stagger = nil
+                if launched < len(d.servers) {
+                        stagger = time.After(resolverStagger)
+                }
+        }
+
+        launch()
+
+        var lastErr error
+        for pending > 0 {
+                select {
+                case ans := <-answers:
+                        pending--
+                        // Jawaban negatif juga final, agar type yang memang kosong (CAA, TLSA,
+                        // HTTPS) tidak di-query ke semua resolver
+                        if ans.err == nil || IsNXDomain(ans.err) {
+                                return ans.results, ans.err
+                        }
+                        lastErr = ans.err
+
+                        // Server gagal, langsung coba server berikutnya tanpa menunggu stagger
+                        if launched < len(d.servers) {
+                                launch()
+                        }
+                case <-stagger:
+                        launch()
+                }
+        }
+
+        return nil, lastErr
+}
+
+// RcodeError adalah response DNS dengan rcode selain NOERROR
+type RcodeError struct {
+        Rcode int
+}
+
+func (e *RcodeError) Error() string {
+        return fmt.Sprintf("DNS query failed with rcode: %d", e.Rcode)
+}
+
+// IsNXDomain mengecek apakah error berasal dari jawaban NXDOMAIN
+func IsNXDomain(err error) bool {
+        var rcodeErr *RcodeError
+        return errors.As(err, &rcodeErr) && rcodeErr.Rcode == dns.RcodeNameError
+}
+
+// queryClassic melakukan satu lookup ke server tertentu dan mem-parse answer
+func (d *DNSResolver) queryClassic(server, domain string, qtype uint16) ([]string, error) {
+        msg := new(dns.Msg)
+        msg.SetQuestion(dns.Fqdn(domain), qtype)
+        msg.RecursionDesired = true
+
+        resp, _, err := d.send(msg, server)
+        if err != nil {
+                return nil, err
+        }
+
+        if resp.Rcode != dns.RcodeSuccess {
+                return nil, &RcodeError{Rcode: resp.Rcode}
+        }
+
+        // Parse answers (CNAME di chain dilewati kecuali yang diminta)
         var results []string
+        for _, ans := range resp.Answer {
+                if ans.Header().Rrtype != qtype {
+                        continue
+                }
+
+                value := d.extractRecordValue(ans)
+                if value != "" {
+                        results = append(results, value)
+                }
+        }
+
+        return results, nil
+}
+
+// exchange mengirim pesan DNS ke server secara bergantian dan mengembalikan
+// response pertama yang NOERROR atau NXDOMAIN
+func (d *DNSResolver) exchange(msg *dns.Msg) (*dns.Msg, error) {
         var lastErr error
 
-        // Try multiple servers untuk redundancy
         for _, server := range d.servers {
-                msg := new(dns.Msg)
-                msg.SetQuestion(dns.Fqdn(domain), qtype)
-                msg.RecursionDesired = true
-
-                resp, _, err := d.client.Exchange(msg, server)
+                resp, _, err := d.send(msg, server)
                 if err != nil {
                         lastErr = err
                         continue
                 }
 
-                if resp.Rcode != dns.RcodeSuccess {
+                if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
                         lastErr = fmt.Errorf("DNS query failed with rcode: %d", resp.Rcode)
                         continue
                 }
 
-                // Parse answers
-                for _, ans := range resp.Answer {
-                        value := d.extractRecordValue(ans)
-                        if value != "" {
-                                results = append(results, value)
-                        }
+                return resp, nil
+        }
+
+        if lastErr == nil {
+                lastErr = fmt.Errorf("no DNS servers configured")
+        }
+        return nil, lastErr
+}
+
+// send mengirim satu query ke server tertentu dengan memperhatikan rate limit.
+// EDNS0 ditambahkan jika belum ada, response truncated (TC=1) diulang lewat TCP
+// dan SERVFAIL di-retry dengan backoff
+func (d *DNSResolver) send(msg *dns.Msg, server string) (*dns.Msg, time.Duration, error) {
+        if msg.IsEdns0() == nil {
+                msg.SetEdns0(d.ednsSize, false)
+        }
+
+        backoff := servfailBackoff
+        for attempt := 0; ; attempt++ {
+                resp, rtt, err := d.sendOnce(msg, server)
+                if err != nil || resp.Rcode != dns.RcodeServerFailure || attempt >= servfailRetries {
+                        return resp, rtt, err
                 }
 
-                if len(results) > 0 {
-                        break
+                d.logger.Debug(fmt.Sprintf("SERVFAIL dari %s untuk %s, retry dalam %s", server, msg.Question[0].Name, backoff))
+
+                select {
+                case <-d.ctx.Done():
+                        return resp, rtt, nil
+                case <-time.After(backoff):
                 }
+                backoff *= 2
         }
+}
+
+// sendOnce melakukan satu exchange UDP dengan fallback TCP saat truncated
+func (d *DNSResolver) sendOnce(msg *dns.Msg, server string) (*dns.Msg, time.Duration, error) {
+        d.limiter.Wait()
+
+        ctx, cancel := context.WithTimeout(d.ctx, d.client.Timeout)
+        defer cancel()
 
-        if len(results) == 0 && lastErr != nil {
-                return nil, lastErr
+        // Proxy/TOR aktif: query lewat TCP melalui dialer agar tidak bocor
+        conn, err := d.proxyConn(ctx, server)
+        if err != nil {
+                return nil, 0, err
+        }
+        if conn != nil {
+                defer conn.Close()
+                return d.tcpClient.ExchangeWithConn(msg, conn)
         }
 
-        return results, nil
+        resp, rtt, err := d.client.ExchangeContext(ctx, msg, server)
+        if err != nil || !resp.Truncated {
+                return resp, rtt, err
+        }
+
+        d.logger.Debug(fmt.Sprintf("Response truncated dari %s untuk %s, retry via TCP", server, msg.Question[0].Name))
+
+        d.limiter.Wait()
+        tcpResp, tcpRTT, tcpErr := d.tcpClient.ExchangeContext(ctx, msg, server)
+        if tcpErr != nil {
+                // Tetap kembalikan jawaban parsial UDP jika TCP gagal
+                d.logger.Debug(fmt.Sprintf("TCP fallback ke %s gagal: %v", server, tcpErr))
+                return resp, rtt, nil
+        }
+        return tcpResp, tcpRTT, nil
 }
 
 // lookupDoH melakukan DNS lookup menggunakan DNS over HTTPS
@@ -212,11 +658,38 @@ func (d *DNSResolver) extractRecordValue(rr dns.RR) string {
                 return strings.Join(v.Txt, " ")
         case *dns.PTR:
                 return strings.TrimSuffix(v.Ptr, ".")
+        case *dns.SOA:
+                return fmt.Sprintf("%s %s serial=%d refresh=%d retry=%d expire=%d minimum=%d",
+                        strings.TrimSuffix(v.Ns, "."), strings.TrimSuffix(v.Mbox, "."),
+                        v.Serial, v.Refresh, v.Retry, v.Expire, v.Minttl)
+        case *dns.CAA:
+                return fmt.Sprintf("%d %s \"%s\"", v.Flag, v.Tag, v.Value)
+        case *dns.SRV:
+                return fmt.Sprintf("%d %d %d %s", v.Priority, v.Weight, v.Port, strings.TrimSuffix(v.Target, "."))
+        case *dns.HTTPS:
+                return d.formatSVCB(&v.SVCB)
+        case *dns.SVCB:
+                return d.formatSVCB(v)
+        case *dns.DS:
+                return fmt.Sprintf("%d %s %d %s", v.KeyTag, dns.AlgorithmToString[v.Algorithm], v.DigestType, strings.ToLower(v.Digest))
+        case *dns.TLSA:
+                return fmt.Sprintf("%d %d %d %s", v.Usage, v.Selector, v.MatchingType, v.Certificate)
         default:
                 return ""
         }
 }
 
+// formatSVCB memformat SVCB/HTTPS: priority, target, lalu params (alpn, ech, ipv4hint, ...)
+func (d *DNSResolver) formatSVCB(v *dns.SVCB) string {
+        parts := []string{fmt.Sprintf("%d", v.Priority), v.Target}
+
+        for _, kv := range v.Value {
+                parts = append(parts, kv.Key().String()+"="+kv.String())
+        }
+
+        return strings.Join(parts, " ")
+}
+
 // ReverseLookup melakukan reverse DNS lookup
 func (d *DNSResolver) ReverseLookup(ip string) ([]string, error) {
         addr, err := dns.ReverseAddr(ip)
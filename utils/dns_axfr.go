@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// ZoneTransferAttempt menyimpan hasil AXFR/IXFR ke satu nameserver
+type ZoneTransferAttempt struct {
+	Nameserver string `json:"nameserver"`
+	Address    string `json:"address"`
+	Type       string `json:"type"`
+	Allowed    bool   `json:"allowed"`
+	Records    int    `json:"records,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ZoneTransferResult menyimpan hasil pengecekan zone transfer untuk satu domain
+type ZoneTransferResult struct {
+	Domain    string                `json:"domain"`
+	Exposed   bool                  `json:"exposed"`
+	Attempts  []ZoneTransferAttempt `json:"attempts"`
+	Hosts     []string              `json:"hosts,omitempty"`
+	Records   []DNSRecord           `json:"records,omitempty"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+// ZoneApex mengembalikan apex zone yang memuat name, yaitu nama terdekat ke atas yang punya SOA sendiri
+func (d *DNSResolver) ZoneApex(name string) (string, error) {
+	for zone := strings.Trim(strings.ToLower(name), "."); zone != "."; zone = ParentZone(zone) {
+		if d.isZoneApex(zone) {
+			return zone, nil
+		}
+	}
+	return "", fmt.Errorf("apex zone untuk %s tidak ditemukan", name)
+}
+
+// CheckZoneTransfer mencoba AXFR dan IXFR ke setiap nameserver domain lewat TCP
+func (d *DNSResolver) CheckZoneTransfer(domain string, nameservers []string) *ZoneTransferResult {
+	zone := dns.Fqdn(domain)
+	result := &ZoneTransferResult{
+		Domain:    strings.TrimSuffix(domain, "."),
+		Attempts:  make([]ZoneTransferAttempt, 0),
+		Timestamp: time.Now(),
+	}
+
+	hosts := make(map[string]bool)
+	seenRecords := make(map[string]bool)
+
+	for _, ns := range nameservers {
+		// Nameserver IPv6-only juga dicoba lewat AAAA
+		addresses := d.resolveNameserver(ns)
+		if len(addresses) == 0 {
+			result.Attempts = append(result.Attempts, ZoneTransferAttempt{
+				Nameserver: ns,
+				Type:       "AXFR",
+				Error:      "nameserver tidak dapat di-resolve (A/AAAA)",
+			})
+			continue
+		}
+
+		for _, ip := range addresses {
+			address := net.JoinHostPort(ip, "53")
+
+			for _, qtype := range []uint16{dns.TypeAXFR, dns.TypeIXFR} {
+				attempt := ZoneTransferAttempt{
+					Nameserver: ns,
+					Address:    address,
+					Type:       dns.TypeToString[qtype],
+				}
+
+				records, err := d.attemptTransfer(zone, address, qtype)
+				if err != nil {
+					attempt.Error = err.Error()
+				} else {
+					attempt.Allowed = true
+					attempt.Records = len(records)
+					result.Exposed = true
+
+					for _, rr := range records {
+						record := d.rrToRecord(rr)
+						key := record.Name + " " + record.Type + " " + record.Value
+						if !seenRecords[key] {
+							seenRecords[key] = true
+							result.Records = append(result.Records, record)
+						}
+
+						switch rr.Header().Rrtype {
+						case dns.TypeA, dns.TypeAAAA, dns.TypeCNAME:
+							if !strings.HasPrefix(record.Name, "*") {
+								hosts[record.Name] = true
+							}
+						}
+					}
+
+					d.logger.Warn(fmt.Sprintf("🚨 %s diizinkan oleh %s (%s): %d records", attempt.Type, ns, address, len(records)))
+				}
+
+				result.Attempts = append(result.Attempts, attempt)
+			}
+		}
+	}
+
+	for host := range hosts {
+		result.Hosts = append(result.Hosts, host)
+	}
+	sort.Strings(result.Hosts)
+
+	return result
+}
+
+// attemptTransfer melakukan satu zone transfer dan mengembalikan seluruh records
+func (d *DNSResolver) attemptTransfer(zone, address string, qtype uint16) ([]dns.RR, error) {
+	msg := new(dns.Msg)
+	if qtype == dns.TypeIXFR {
+		// Serial 0 memaksa server mengirim zone lengkap jika IXFR diizinkan
+		msg.SetIxfr(zone, 0, ".", ".")
+	} else {
+		msg.SetAxfr(zone)
+	}
+
+	transfer := &dns.Transfer{
+		DialTimeout:  d.client.Timeout,
+		ReadTimeout:  d.client.Timeout,
+		WriteTimeout: d.client.Timeout,
+	}
+
+	conn, err := d.proxyConn(d.ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	transfer.Conn = conn
+
+	envelopes, err := transfer.In(msg, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []dns.RR
+	var transferErr error
+	for envelope := range envelopes {
+		if envelope.Error != nil {
+			transferErr = envelope.Error
+			continue
+		}
+		records = append(records, envelope.RR...)
+	}
+
+	if transferErr != nil {
+		return nil, transferErr
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("zone transfer kosong")
+	}
+
+	return records, nil
+}
+
+// rrToRecord mengkonversi dns.RR menjadi DNSRecord
+func (d *DNSResolver) rrToRecord(rr dns.RR) DNSRecord {
+	header := rr.Header()
+
+	value := d.extractRecordValue(rr)
+	if value == "" {
+		value = strings.TrimSpace(strings.TrimPrefix(rr.String(), header.String()))
+	}
+
+	return DNSRecord{
+		Name:  strings.TrimSuffix(strings.ToLower(header.Name), "."),
+		Type:  dns.TypeToString[header.Rrtype],
+		Value: value,
+		TTL:   header.Ttl,
+	}
+}
@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// cgnatRange adalah shared address space RFC 6598 yang tidak dicakup net.IP.IsPrivate
+var _, cgnatRange, _ = net.ParseCIDR("100.64.0.0/10")
+
+// ResolverAnswer menyimpan jawaban satu resolver untuk perbandingan
+type ResolverAnswer struct {
+	Server  string   `json:"server"`
+	Rcode   string   `json:"rcode,omitempty"`
+	Answers []string `json:"answers,omitempty"`
+	MinTTL  uint32   `json:"min_ttl,omitempty"`
+	MaxTTL  uint32   `json:"max_ttl,omitempty"`
+	RTT     string   `json:"rtt,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ResolverComparison menyimpan hasil perbandingan jawaban antar resolver
+type ResolverComparison struct {
+	Name       string           `json:"name"`
+	Type       string           `json:"type"`
+	Consistent bool             `json:"consistent"`
+	Answers    []ResolverAnswer `json:"answers"`
+	Findings   []Finding        `json:"findings,omitempty"`
+	Timestamp  time.Time        `json:"timestamp"`
+}
+
+// CompareResolvers meng-query nama yang sama ke setiap resolver dan membandingkan hasilnya
+func (d *DNSResolver) CompareResolvers(name, recordType string) (*ResolverComparison, error) {
+	qtype, ok := dns.StringToType[strings.ToUpper(recordType)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+
+	comparison := &ResolverComparison{
+		Name:      strings.TrimSuffix(name, "."),
+		Type:      dns.TypeToString[qtype],
+		Answers:   make([]ResolverAnswer, len(d.servers)),
+		Findings:  make([]Finding, 0),
+		Timestamp: time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, server := range d.servers {
+		wg.Add(1)
+		go func(idx int, srv string) {
+			defer wg.Done()
+			comparison.Answers[idx] = d.queryServer(srv, name, qtype)
+		}(i, server)
+	}
+	wg.Wait()
+
+	d.evaluateComparison(comparison)
+	comparison.Consistent = len(comparison.Findings) == 0
+
+	return comparison, nil
+}
+
+// queryServer melakukan satu query langsung ke server tertentu
+func (d *DNSResolver) queryServer(server, name string, qtype uint16) ResolverAnswer {
+	answer := ResolverAnswer{Server: server}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+
+	resp, rtt, err := d.send(msg, server)
+	if err != nil {
+		answer.Error = err.Error()
+		return answer
+	}
+
+	answer.Rcode = dns.RcodeToString[resp.Rcode]
+	answer.RTT = rtt.Round(time.Millisecond).String()
+
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+
+		answer.Answers = append(answer.Answers, d.extractRecordValue(rr))
+
+		ttl := rr.Header().Ttl
+		if answer.MinTTL == 0 || ttl < answer.MinTTL {
+			answer.MinTTL = ttl
+		}
+		if ttl > answer.MaxTTL {
+			answer.MaxTTL = ttl
+		}
+	}
+	sort.Strings(answer.Answers)
+
+	return answer
+}
+
+// evaluateComparison mencari inkonsistensi rcode, answer set, IP privat dan TTL
+func (d *DNSResolver) evaluateComparison(comparison *ResolverComparison) {
+	var answered []ResolverAnswer
+	sets := make(map[string][]string)
+
+	for _, answer := range comparison.Answers {
+		if answer.Error != "" {
+			continue
+		}
+		if answer.Rcode == "NOERROR" && len(answer.Answers) > 0 {
+			answered = append(answered, answer)
+			key := strings.Join(answer.Answers, ",")
+			sets[key] = append(sets[key], answer.Server)
+		}
+	}
+
+	// NXDOMAIN/SERVFAIL dari satu resolver padahal resolver lain menjawab
+	if len(answered) > 0 {
+		for _, answer := range comparison.Answers {
+			if answer.Error == "" && answer.Rcode != "NOERROR" {
+				comparison.addFinding(SeverityHigh, "Rcode tidak konsisten",
+					fmt.Sprintf("%s menjawab %s, %d resolver lain menjawab NOERROR", answer.Server, answer.Rcode, len(answered)))
+			}
+		}
+	}
+
+	// Answer set berbeda (bisa GeoDNS/CDN, bisa juga tampering)
+	if len(sets) > 1 {
+		var evidence []string
+		for set, servers := range sets {
+			evidence = append(evidence, fmt.Sprintf("%s=[%s]", strings.Join(servers, "+"), set))
+		}
+		sort.Strings(evidence)
+		comparison.addFinding(SeverityMedium, "Answer set berbeda antar resolver", strings.Join(evidence, " "))
+	}
+
+	// IP privat yang hanya muncul di sebagian resolver
+	for _, answer := range answered {
+		for _, value := range answer.Answers {
+			if isInternalIP(value) && !allResolversReturned(answered, value) {
+				comparison.addFinding(SeverityHigh, "IP privat disisipkan",
+					fmt.Sprintf("%s mengembalikan %s", answer.Server, value))
+			}
+		}
+	}
+
+	// TTL jauh lebih besar dari resolver lain (indikasi cache poisoning)
+	for _, answer := range answered {
+		var othersMax uint32
+		for _, other := range answered {
+			if other.Server != answer.Server && other.MaxTTL > othersMax {
+				othersMax = other.MaxTTL
+			}
+		}
+		if othersMax > 0 && answer.MaxTTL > othersMax*2 {
+			comparison.addFinding(SeverityLow, "TTL tidak wajar",
+				fmt.Sprintf("%s TTL %d, resolver lain maksimum %d", answer.Server, answer.MaxTTL, othersMax))
+		}
+	}
+}
+
+// addFinding menambahkan finding kategori dns
+func (c *ResolverComparison) addFinding(severity, title, detail string) {
+	c.Findings = append(c.Findings, NewFinding(severity, "dns", title, detail))
+}
+
+// allResolversReturned mengecek apakah semua resolver mengembalikan value yang sama
+func allResolversReturned(answers []ResolverAnswer, value string) bool {
+	for _, answer := range answers {
+		found := false
+		for _, item := range answer.Answers {
+			if item == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isInternalIP mengecek apakah IP termasuk private, loopback, link-local atau unspecified
+func isInternalIP(value string) bool {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return false
+	}
+
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsUnspecified() || cgnatRange.Contains(ip)
+}
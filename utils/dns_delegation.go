@@ -0,0 +1,368 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// NameserverCheck menyimpan hasil pengecekan satu nameserver delegasi
+type NameserverCheck struct {
+	Name     string   `json:"name"`
+	IPs      []string `json:"ips,omitempty"`
+	Glue     []string `json:"glue,omitempty"`
+	ASNs     []string `json:"asns,omitempty"`
+	InParent bool     `json:"in_parent"`
+	InChild  bool     `json:"in_child"`
+	Lame     bool     `json:"lame"`
+	LameIPs  []string `json:"lame_ips,omitempty"`
+	Serial   uint32   `json:"serial,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// DelegationResult menyimpan hasil audit delegasi NS sebuah zone
+type DelegationResult struct {
+	Domain      string            `json:"domain"`
+	ParentZone  string            `json:"parent_zone"`
+	ParentNS    []string          `json:"parent_ns"`
+	ChildNS     []string          `json:"child_ns"`
+	Nameservers []NameserverCheck `json:"nameservers"`
+	Findings    []Finding         `json:"findings,omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+}
+
+// AuditDelegation membandingkan NS di parent zone dengan NS di child, meng-query SOA
+// langsung ke setiap server authoritative dan melaporkan lame delegation, serial
+// mismatch, konsentrasi ASN dan glue yang hilang
+func (d *DNSResolver) AuditDelegation(domain string, nameservers []string) *DelegationResult {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	result := &DelegationResult{
+		Domain:      domain,
+		Nameservers: make([]NameserverCheck, 0),
+		Findings:    make([]Finding, 0),
+		Timestamp:   time.Now(),
+	}
+
+	// NS dari sudut pandang parent (referral + glue)
+	parentZone, parentNS, glue, err := d.queryParentDelegation(domain)
+	result.ParentZone = parentZone
+	result.ParentNS = parentNS
+	if err != nil {
+		result.addFinding(SeverityHigh, "Delegasi parent tidak ditemukan", err.Error())
+	}
+
+	if len(nameservers) == 0 {
+		nameservers, _ = d.LookupNS(domain)
+	}
+
+	checks := make(map[string]*NameserverCheck)
+	getCheck := func(name string) *NameserverCheck {
+		name = strings.TrimSuffix(strings.ToLower(name), ".")
+		if _, ok := checks[name]; !ok {
+			checks[name] = &NameserverCheck{Name: name}
+		}
+		return checks[name]
+	}
+
+	for _, ns := range parentNS {
+		check := getCheck(ns)
+		check.InParent = true
+		check.Glue = glue[check.Name]
+	}
+
+	// NS dari sudut pandang child: gabungan resolver dan jawaban authoritative
+	childNS := make(map[string]bool)
+	for _, ns := range nameservers {
+		childNS[strings.TrimSuffix(strings.ToLower(ns), ".")] = true
+	}
+
+	serials := make(map[uint32][]string)
+	var allIPs []string
+
+	for _, ns := range appendUnique(append([]string{}, parentNS...), nameservers...) {
+		check := getCheck(ns)
+		check.IPs = d.resolveNameserver(check.Name)
+		if len(check.IPs) == 0 {
+			check.Error = "nameserver tidak dapat di-resolve"
+			check.Lame = true
+			continue
+		}
+		allIPs = append(allIPs, check.IPs...)
+
+		for _, ip := range check.IPs {
+			soa, authNS, err := d.queryAuthoritative(domain, ip)
+			if err != nil {
+				check.LameIPs = append(check.LameIPs, ip)
+				check.Error = err.Error()
+				continue
+			}
+
+			check.Serial = soa.Serial
+			serials[soa.Serial] = appendUnique(serials[soa.Serial], check.Name)
+			for _, name := range authNS {
+				childNS[name] = true
+			}
+		}
+
+		check.Lame = len(check.LameIPs) == len(check.IPs)
+
+		for _, ip := range check.IPs {
+			if asn := d.lookupASN(ip); asn != "" {
+				check.ASNs = appendUnique(check.ASNs, asn)
+			}
+		}
+	}
+
+	for name := range childNS {
+		getCheck(name).InChild = true
+		result.ChildNS = append(result.ChildNS, name)
+	}
+	sort.Strings(result.ChildNS)
+
+	for _, check := range checks {
+		result.Nameservers = append(result.Nameservers, *check)
+	}
+	sort.Slice(result.Nameservers, func(i, j int) bool {
+		return result.Nameservers[i].Name < result.Nameservers[j].Name
+	})
+
+	d.evaluateDelegation(result, serials, allIPs)
+
+	return result
+}
+
+// evaluateDelegation mengubah hasil pengecekan nameserver menjadi findings
+func (d *DNSResolver) evaluateDelegation(result *DelegationResult, serials map[uint32][]string, allIPs []string) {
+	var parentOnly, childOnly []string
+	asns := make(map[string]bool)
+
+	for _, check := range result.Nameservers {
+		switch {
+		case check.InParent && !check.InChild:
+			parentOnly = append(parentOnly, check.Name)
+		case check.InChild && !check.InParent && len(result.ParentNS) > 0:
+			childOnly = append(childOnly, check.Name)
+		}
+
+		if check.Lame {
+			result.addFinding(SeverityHigh, "Lame delegation",
+				fmt.Sprintf("%s tidak authoritative untuk %s: %s", check.Name, result.Domain, check.Error))
+		} else if len(check.LameIPs) > 0 {
+			result.addFinding(SeverityMedium, "Lame delegation sebagian",
+				fmt.Sprintf("%s tidak authoritative di %s", check.Name, strings.Join(check.LameIPs, ", ")))
+		}
+
+		// NS di dalam zone sendiri wajib punya glue di parent
+		if check.InParent && len(check.Glue) == 0 && isSubdomainOf(check.Name, result.Domain) {
+			result.addFinding(SeverityHigh, "Glue record hilang",
+				fmt.Sprintf("%s berada di dalam %s tetapi parent tidak mengirim glue A/AAAA", check.Name, result.Domain))
+		}
+
+		for _, asn := range check.ASNs {
+			asns[asn] = true
+		}
+	}
+
+	if len(parentOnly) > 0 || len(childOnly) > 0 {
+		result.addFinding(SeverityMedium, "NS parent/child tidak sama",
+			fmt.Sprintf("hanya di parent: [%s], hanya di child: [%s]", strings.Join(parentOnly, ", "), strings.Join(childOnly, ", ")))
+	}
+
+	if len(serials) > 1 {
+		var evidence []string
+		for serial, servers := range serials {
+			evidence = append(evidence, fmt.Sprintf("%d=[%s]", serial, strings.Join(servers, ",")))
+		}
+		sort.Strings(evidence)
+		result.addFinding(SeverityMedium, "Serial SOA tidak sinkron", strings.Join(evidence, " "))
+	}
+
+	if len(result.Nameservers) == 1 {
+		result.addFinding(SeverityMedium, "Kurang dari 2 nameserver", "RFC 2182 merekomendasikan minimal 2 nameserver terpisah")
+	}
+
+	if len(asns) == 1 && len(allIPs) > 1 {
+		var asn string
+		for key := range asns {
+			asn = key
+		}
+		result.addFinding(SeverityMedium, "Nameserver dalam satu ASN",
+			fmt.Sprintf("semua %d alamat nameserver berada di AS%s (single point of failure)", len(allIPs), asn))
+	}
+}
+
+// queryParentDelegation mencari parent zone dan meminta referral NS beserta glue
+func (d *DNSResolver) queryParentDelegation(domain string) (string, []string, map[string][]string, error) {
+	glue := make(map[string][]string)
+
+	parent := ParentZone(domain)
+	var parentServers []string
+	for {
+		if ns, err := d.LookupNS(parent); err == nil && len(ns) > 0 {
+			parentServers = ns
+			break
+		}
+		if parent == "." {
+			return parent, nil, glue, fmt.Errorf("NS parent zone untuk %s tidak ditemukan", domain)
+		}
+		parent = ParentZone(parent)
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(domain), dns.TypeNS)
+	msg.RecursionDesired = false
+
+	var lastErr error
+	for _, server := range parentServers {
+		for _, ip := range d.resolveNameserver(server) {
+			resp, _, err := d.send(msg, net.JoinHostPort(ip, "53"))
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			var names []string
+			// Referral ada di authority, server yang juga authoritative untuk child menjawab di answer
+			for _, rr := range append(resp.Ns, resp.Answer...) {
+				if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, dns.Fqdn(domain)) {
+					names = appendUnique(names, strings.TrimSuffix(strings.ToLower(ns.Ns), "."))
+				}
+			}
+
+			if len(names) == 0 {
+				lastErr = fmt.Errorf("%s (%s) tidak mengembalikan delegasi untuk %s (%s)",
+					server, ip, domain, dns.RcodeToString[resp.Rcode])
+				continue
+			}
+
+			for _, rr := range resp.Extra {
+				name := strings.TrimSuffix(strings.ToLower(rr.Header().Name), ".")
+				switch v := rr.(type) {
+				case *dns.A:
+					glue[name] = appendUnique(glue[name], v.A.String())
+				case *dns.AAAA:
+					glue[name] = appendUnique(glue[name], v.AAAA.String())
+				}
+			}
+
+			sort.Strings(names)
+			return parent, names, glue, nil
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("nameserver parent %s tidak dapat di-resolve", parent)
+	}
+	return parent, nil, glue, lastErr
+}
+
+// queryAuthoritative meng-query SOA dan NS langsung ke server dan memastikan jawaban authoritative
+func (d *DNSResolver) queryAuthoritative(domain, ip string) (*dns.SOA, []string, error) {
+	address := net.JoinHostPort(ip, "53")
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(domain), dns.TypeSOA)
+	msg.RecursionDesired = false
+
+	resp, _, err := d.send(msg, address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, nil, fmt.Errorf("%s menjawab %s", ip, dns.RcodeToString[resp.Rcode])
+	}
+	if !resp.Authoritative {
+		return nil, nil, fmt.Errorf("%s menjawab tanpa AA flag", ip)
+	}
+
+	var soa *dns.SOA
+	for _, rr := range resp.Answer {
+		if record, ok := rr.(*dns.SOA); ok {
+			soa = record
+			break
+		}
+	}
+	if soa == nil {
+		return nil, nil, fmt.Errorf("%s tidak mengembalikan SOA", ip)
+	}
+
+	// NS set yang disajikan child
+	msg = new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(domain), dns.TypeNS)
+	msg.RecursionDesired = false
+
+	var names []string
+	if resp, _, err := d.send(msg, address); err == nil {
+		for _, rr := range resp.Answer {
+			if ns, ok := rr.(*dns.NS); ok {
+				names = append(names, strings.TrimSuffix(strings.ToLower(ns.Ns), "."))
+			}
+		}
+	}
+
+	return soa, names, nil
+}
+
+// resolveNameserver me-resolve alamat IPv4 dan IPv6 sebuah nameserver
+func (d *DNSResolver) resolveNameserver(name string) []string {
+	ips, _ := d.LookupA(name)
+	if v6, err := d.LookupAAAA(name); err == nil {
+		ips = append(ips, v6...)
+	}
+	return ips
+}
+
+// lookupASN mencari origin ASN sebuah IP melalui Team Cymru IP-to-ASN DNS
+func (d *DNSResolver) lookupASN(ip string) string {
+	reverse, err := dns.ReverseAddr(ip)
+	if err != nil {
+		return ""
+	}
+
+	var query string
+	switch {
+	case strings.HasSuffix(reverse, ".in-addr.arpa."):
+		query = strings.TrimSuffix(reverse, "in-addr.arpa.") + "origin.asn.cymru.com"
+	case strings.HasSuffix(reverse, ".ip6.arpa."):
+		query = strings.TrimSuffix(reverse, "ip6.arpa.") + "origin6.asn.cymru.com"
+	default:
+		return ""
+	}
+
+	records, err := d.LookupTXT(query)
+	if err != nil || len(records) == 0 {
+		return ""
+	}
+
+	// Format: "15169 | 8.8.8.0/24 | US | arin | 1992-12-01"
+	fields := strings.Fields(strings.SplitN(records[0], "|", 2)[0])
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// addFinding menambahkan finding kategori dns
+func (r *DelegationResult) addFinding(severity, title, detail string) {
+	r.Findings = append(r.Findings, NewFinding(severity, "dns", title, detail))
+}
+
+// ParentZone mengembalikan nama satu level di atas (root untuk TLD)
+func ParentZone(name string) string {
+	name = strings.Trim(name, ".")
+	if idx := strings.Index(name, "."); idx >= 0 {
+		return name[idx+1:]
+	}
+	return "."
+}
+
+// isSubdomainOf mengecek apakah name sama dengan atau berada di bawah zone
+func isSubdomainOf(name, zone string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+	zone = strings.TrimSuffix(strings.ToLower(zone), ".")
+	return zone == "" || name == zone || strings.HasSuffix(name, "."+zone)
+}
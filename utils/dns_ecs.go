@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultECSSubnets adalah client subnet sintetis per region (prefix ISP besar)
+var DefaultECSSubnets = []string{
+	"us=24.0.0.0/24",
+	"br=177.0.0.0/24",
+	"de=84.128.0.0/24",
+	"fr=90.0.0.0/24",
+	"jp=126.0.0.0/24",
+	"id=36.64.0.0/24",
+	"au=1.120.0.0/24",
+}
+
+// ECSAnswer menyimpan jawaban satu resolver untuk satu client subnet
+type ECSAnswer struct {
+	Region  string   `json:"region,omitempty"`
+	Subnet  string   `json:"subnet"`
+	Scope   uint8    `json:"scope_prefix"`
+	Rcode   string   `json:"rcode,omitempty"`
+	Answers []string `json:"answers,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ECSResolverProbe menyimpan hasil probing ECS ke satu resolver
+type ECSResolverProbe struct {
+	Server    string      `json:"server"`
+	Supported bool        `json:"ecs_supported"`
+	Probes    []ECSAnswer `json:"probes"`
+}
+
+// ECSProbeResult menyimpan pemetaan edge address per region dari probing ECS
+type ECSProbeResult struct {
+	Name      string              `json:"name"`
+	Type      string              `json:"type"`
+	Resolvers []ECSResolverProbe  `json:"resolvers"`
+	Edges     map[string][]string `json:"edges,omitempty"`
+	Timestamp time.Time           `json:"timestamp"`
+}
+
+// ecsSubnet adalah client subnet yang sudah di-parse
+type ecsSubnet struct {
+	region  string
+	network *net.IPNet
+}
+
+// ProbeECS mengirim query dengan EDNS Client Subnet untuk setiap subnet ke setiap resolver.
+// Subnet berformat "region=cidr" atau "cidr"
+func (d *DNSResolver) ProbeECS(name, recordType string, subnets []string) (*ECSProbeResult, error) {
+	qtype, ok := dns.StringToType[strings.ToUpper(recordType)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+
+	if len(subnets) == 0 {
+		subnets = DefaultECSSubnets
+	}
+
+	var parsed []ecsSubnet
+	for _, subnet := range subnets {
+		item, err := parseECSSubnet(subnet)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, item)
+	}
+
+	result := &ECSProbeResult{
+		Name:      strings.TrimSuffix(name, "."),
+		Type:      dns.TypeToString[qtype],
+		Resolvers: make([]ECSResolverProbe, len(d.servers)),
+		Edges:     make(map[string][]string),
+		Timestamp: time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, server := range d.servers {
+		result.Resolvers[i] = ECSResolverProbe{
+			Server: server,
+			Probes: make([]ECSAnswer, len(parsed)),
+		}
+
+		for j, subnet := range parsed {
+			wg.Add(1)
+			go func(srvIdx, subIdx int, srv string, sub ecsSubnet) {
+				defer wg.Done()
+				result.Resolvers[srvIdx].Probes[subIdx] = d.queryECS(srv, name, qtype, sub)
+			}(i, j, server, subnet)
+		}
+	}
+	wg.Wait()
+
+	// Resolver dianggap mendukung ECS jika mengembalikan scope prefix > 0
+	for i := range result.Resolvers {
+		probe := &result.Resolvers[i]
+		for _, answer := range probe.Probes {
+			if answer.Scope > 0 {
+				probe.Supported = true
+			}
+		}
+
+		if !probe.Supported {
+			continue
+		}
+
+		for _, answer := range probe.Probes {
+			label := answer.Subnet
+			if answer.Region != "" {
+				label = answer.Region
+			}
+			for _, value := range answer.Answers {
+				result.Edges[value] = appendUnique(result.Edges[value], label)
+			}
+		}
+	}
+
+	for value := range result.Edges {
+		sort.Strings(result.Edges[value])
+	}
+
+	return result, nil
+}
+
+// queryECS melakukan satu query dengan opsi EDNS0 client subnet
+func (d *DNSResolver) queryECS(server, name string, qtype uint16, subnet ecsSubnet) ECSAnswer {
+	answer := ECSAnswer{
+		Region: subnet.region,
+		Subnet: subnet.network.String(),
+	}
+
+	ones, _ := subnet.network.Mask.Size()
+	option := &dns.EDNS0_SUBNET{
+		Code:          dns.EDNS0SUBNET,
+		Family:        1,
+		SourceNetmask: uint8(ones),
+		Address:       subnet.network.IP,
+	}
+	if subnet.network.IP.To4() == nil {
+		option.Family = 2
+	}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+	msg.SetEdns0(d.ednsSize, false)
+	opt := msg.IsEdns0()
+	opt.Option = append(opt.Option, option)
+
+	resp, _, err := d.send(msg, server)
+	if err != nil {
+		answer.Error = err.Error()
+		return answer
+	}
+
+	answer.Rcode = dns.RcodeToString[resp.Rcode]
+
+	if respOpt := resp.IsEdns0(); respOpt != nil {
+		for _, item := range respOpt.Option {
+			if ecs, ok := item.(*dns.EDNS0_SUBNET); ok {
+				answer.Scope = ecs.SourceScope
+			}
+		}
+	}
+
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+		answer.Answers = append(answer.Answers, d.extractRecordValue(rr))
+	}
+	sort.Strings(answer.Answers)
+
+	return answer
+}
+
+// parseECSSubnet mem-parse "region=cidr" atau "cidr" (IP tunggal dianggap /24 atau /56)
+func parseECSSubnet(value string) (ecsSubnet, error) {
+	var subnet ecsSubnet
+
+	value = strings.TrimSpace(value)
+	if idx := strings.Index(value, "="); idx >= 0 {
+		subnet.region = strings.TrimSpace(value[:idx])
+		value = strings.TrimSpace(value[idx+1:])
+	}
+
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return subnet, fmt.Errorf("invalid ECS subnet: %s", value)
+		}
+		if ip.To4() != nil {
+			value += "/24"
+		} else {
+			value += "/56"
+		}
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return subnet, fmt.Errorf("invalid ECS subnet %s: %v", value, err)
+	}
+	if v4 := network.IP.To4(); v4 != nil {
+		network.IP = v4
+	}
+
+	subnet.network = network
+	return subnet, nil
+}
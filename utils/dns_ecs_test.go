@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseECSSubnet(t *testing.T) {
+	tests := []struct {
+		value   string
+		region  string
+		network string
+		wantErr string
+	}{
+		{"203.0.113.0/24", "", "203.0.113.0/24", ""},
+		{"203.0.113.77", "", "203.0.113.0/24", ""},
+		{"id=203.0.113.77/20", "id", "203.0.112.0/20", ""},
+		{" us = 198.51.100.1 ", "us", "198.51.100.0/24", ""},
+		{"2001:db8:1:2::1", "", "2001:db8:1::/56", ""},
+		{"eu=2001:db8::/32", "eu", "2001:db8::/32", ""},
+		{"sg=not-an-ip", "", "", "invalid ECS subnet: not-an-ip"},
+		{"203.0.113.0/40", "", "", "invalid ECS subnet 203.0.113.0/40"},
+		{"", "", "", "invalid ECS subnet"},
+	}
+
+	for _, tt := range tests {
+		subnet, err := parseECSSubnet(tt.value)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseECSSubnet(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseECSSubnet(%q) unexpected error: %v", tt.value, err)
+			continue
+		}
+		if subnet.region != tt.region || subnet.network.String() != tt.network {
+			t.Errorf("parseECSSubnet(%q) = %q %s, want %q %s", tt.value, subnet.region, subnet.network, tt.region, tt.network)
+		}
+	}
+}
+
+func TestParseECSSubnetIPv4Form(t *testing.T) {
+	// ECS family 1 membutuhkan alamat 4 byte, bukan bentuk IPv4-mapped 16 byte
+	subnet, err := parseECSSubnet("192.0.2.0/24")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subnet.network.IP) != 4 {
+		t.Errorf("panjang IP = %d, want 4", len(subnet.network.IP))
+	}
+}
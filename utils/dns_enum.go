@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// SubdomainHit menyimpan satu subdomain yang berhasil di-resolve
+type SubdomainHit struct {
+	Name   string   `json:"name"`
+	IPs    []string `json:"ips,omitempty"`
+	CNAMEs []string `json:"cnames,omitempty"`
+	Depth  int      `json:"depth"`
+}
+
+// EnumResult menyimpan hasil enumerasi subdomain untuk satu domain
+type EnumResult struct {
+	Domain    string             `json:"domain"`
+	Wildcard  *WildcardSignature `json:"wildcard,omitempty"`
+	Tested    int                `json:"tested"`
+	Filtered  int                `json:"filtered_wildcard"`
+	Hits      []SubdomainHit     `json:"hits"`
+	Timestamp time.Time          `json:"timestamp"`
+	Duration  string             `json:"duration"`
+}
+
+// WildcardSignature menyimpan jawaban DNS dari label random di sebuah zone
+type WildcardSignature struct {
+	Zone   string   `json:"zone"`
+	IPs    []string `json:"ips,omitempty"`
+	CNAMEs []string `json:"cnames,omitempty"`
+}
+
+// Matches mengecek apakah jawaban DNS identik dengan jawaban wildcard
+func (w *WildcardSignature) Matches(ips, cnames []string) bool {
+	if w == nil {
+		return false
+	}
+
+	if len(cnames) > 0 && len(w.CNAMEs) > 0 && isSubset(cnames, w.CNAMEs) {
+		return true
+	}
+
+	return len(ips) > 0 && len(w.IPs) > 0 && isSubset(ips, w.IPs)
+}
+
+// WildcardZone mengembalikan zone tempat wildcard untuk name diuji (parent zone), atau "."
+// jika parent berada di atas registrable domain (TLD atau suffix publik seperti co.uk)
+func WildcardZone(name string) string {
+	name = strings.Trim(strings.ToLower(name), ".")
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(name)
+	if err != nil {
+		return "."
+	}
+
+	parent := ParentZone(name)
+	if !isSubdomainOf(parent, registrable) {
+		return "."
+	}
+	return parent
+}
+
+// RegistrableDomain mengembalikan registrable domain (eTLD+1) dari name, atau "" untuk
+// IP, suffix publik dan nama yang tidak valid
+func RegistrableDomain(name string) string {
+	if ValidateIP(name) {
+		return ""
+	}
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(strings.Trim(strings.ToLower(name), "."))
+	if err != nil {
+		return ""
+	}
+	return registrable
+}
+
+// wildcardProbes adalah jumlah label random yang di-query per zone
+const wildcardProbes = 3
+
+// DetectWildcard mendeteksi wildcard DNS dengan me-resolve label random
+func (d *DNSResolver) DetectWildcard(zone string) *WildcardSignature {
+	signature := &WildcardSignature{Zone: zone}
+
+	for i := 0; i < wildcardProbes; i++ {
+		probe := randomLabel() + "." + zone
+
+		ips, cnames := d.resolveHost(probe)
+		signature.IPs = appendUnique(signature.IPs, ips...)
+		signature.CNAMEs = appendUnique(signature.CNAMEs, cnames...)
+	}
+
+	if len(signature.IPs) == 0 && len(signature.CNAMEs) == 0 {
+		return nil
+	}
+
+	d.logger.Debug(fmt.Sprintf("Wildcard DNS terdeteksi di %s: %v %v", zone, signature.IPs, signature.CNAMEs))
+	return signature
+}
+
+// EnumerateSubdomains melakukan brute-force subdomain dari wordlist secara concurrent
+func (d *DNSResolver) EnumerateSubdomains(domain string, words []string, threads int) *EnumResult {
+	startTime := time.Now()
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+
+	result := &EnumResult{
+		Domain:    domain,
+		Wildcard:  d.DetectWildcard(domain),
+		Hits:      make([]SubdomainHit, 0),
+		Timestamp: startTime,
+	}
+
+	if threads <= 0 {
+		threads = 1
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	// Channel untuk limit concurrent query
+	semaphore := make(chan struct{}, threads)
+
+	for _, word := range words {
+		word = strings.Trim(strings.ToLower(word), ".")
+		if word == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func(candidate string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			ips, cnames := d.resolveHost(candidate)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			result.Tested++
+			if len(ips) == 0 && len(cnames) == 0 {
+				return
+			}
+
+			if result.Wildcard.Matches(ips, cnames) {
+				result.Filtered++
+				return
+			}
+
+			result.Hits = append(result.Hits, SubdomainHit{
+				Name:   candidate,
+				IPs:    ips,
+				CNAMEs: cnames,
+			})
+		}(word + "." + domain)
+	}
+
+	wg.Wait()
+
+	sort.Slice(result.Hits, func(i, j int) bool {
+		return result.Hits[i].Name < result.Hits[j].Name
+	})
+
+	result.Duration = time.Since(startTime).Round(time.Millisecond).String()
+	d.logger.Debug(fmt.Sprintf("Enumerasi %s: %d/%d kandidat ditemukan", domain, len(result.Hits), result.Tested))
+
+	return result
+}
+
+// resolveHost me-resolve A, AAAA dan CNAME untuk satu nama
+func (d *DNSResolver) resolveHost(name string) ([]string, []string) {
+	var ips []string
+
+	if aRecords, err := d.LookupA(name); err == nil {
+		ips = append(ips, aRecords...)
+	}
+
+	if aaaaRecords, err := d.LookupAAAA(name); err == nil {
+		ips = append(ips, aaaaRecords...)
+	}
+
+	cnames, _ := d.LookupCNAME(name)
+
+	return ips, cnames
+}
+
+// LoadWordlist membaca wordlist dari file (satu kata per baris, # untuk komentar)
+func LoadWordlist(filename string) ([]string, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var words []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+
+	return words, nil
+}
+
+// randomLabel menghasilkan label DNS random yang hampir pasti tidak ada
+func randomLabel() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("veko%d", time.Now().UnixNano())
+	}
+	return "veko-" + hex.EncodeToString(buf)
+}
+
+// appendUnique menambahkan value ke slice tanpa duplikat
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		exists := false
+		for _, item := range list {
+			if item == value {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// isSubset mengecek apakah semua elemen subset ada di superset
+func isSubset(subset, superset []string) bool {
+	lookup := make(map[string]bool, len(superset))
+	for _, item := range superset {
+		lookup[item] = true
+	}
+
+	for _, item := range subset {
+		if !lookup[item] {
+			return false
+		}
+	}
+	return true
+}
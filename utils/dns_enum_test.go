@@ -0,0 +1,44 @@
+package utils
+
+import "testing"
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"www.example.com", "example.com"},
+		{"Shop.Example.COM.", "example.com"},
+		{"a.b.example.co.uk", "example.co.uk"},
+		{"example.com", "example.com"},
+		{"co.uk", ""},
+		{"com", ""},
+		{"192.0.2.10", ""},
+		{"2001:db8::1", ""},
+	}
+
+	for _, tt := range tests {
+		if got := RegistrableDomain(tt.name); got != tt.want {
+			t.Errorf("RegistrableDomain(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWildcardZone(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"www.example.com", "example.com"},
+		{"a.b.example.com", "b.example.com"},
+		{"example.com", "."},
+		{"www.example.co.uk", "example.co.uk"},
+		{"example.co.uk", "."},
+	}
+
+	for _, tt := range tests {
+		if got := WildcardZone(tt.name); got != tt.want {
+			t.Errorf("WildcardZone(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/proxy"
+)
+
+// SetProxyDialer mengarahkan semua query DNS lewat TCP melalui dialer proxy/TOR.
+// Dalam strict mode query gagal jika proxy gagal, tanpa fallback ke direct DNS
+func (d *DNSResolver) SetProxyDialer(dialer proxy.Dialer, strict bool) {
+	if dialer == nil {
+		return
+	}
+
+	d.dialer = dialer
+	d.strictProxy = strict
+	d.fallbackWarn = &sync.Once{}
+
+	// HTTP (MTA-STS, takeover) ikut lewat proxy, hostname di-resolve di sisi proxy
+	d.httpClient = &http.Client{
+		Timeout: d.httpClient.Timeout,
+		Transport: &http.Transport{
+			DialContext:       d.dialProxy,
+			DisableKeepAlives: true,
+		},
+	}
+
+	mode := "fallback ke direct DNS jika proxy gagal"
+	if strict {
+		mode = "strict, tanpa fallback"
+	}
+	d.logger.Info(fmt.Sprintf("🧅 DNS over TCP melalui proxy (%s)", mode))
+}
+
+// dialProxy membuka koneksi melalui dialer proxy dengan memperhatikan ctx
+func (d *DNSResolver) dialProxy(ctx context.Context, network, address string) (net.Conn, error) {
+	if contextDialer, ok := d.dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, network, address)
+	}
+	return d.dialer.Dial(network, address)
+}
+
+// proxyConn membuka koneksi DNS TCP ke server melalui proxy. Mengembalikan
+// nil tanpa error jika proxy tidak aktif atau gagal di mode non-strict
+// (pemanggil lalu memakai koneksi direct)
+func (d *DNSResolver) proxyConn(ctx context.Context, server string) (*dns.Conn, error) {
+	if d.dialer == nil {
+		return nil, nil
+	}
+
+	conn, err := d.dialProxy(ctx, "tcp", server)
+	if err == nil {
+		return &dns.Conn{Conn: conn}, nil
+	}
+
+	if d.strictProxy {
+		return nil, fmt.Errorf("strict DNS: query ke %s melalui proxy gagal: %v", server, err)
+	}
+
+	d.fallbackWarn.Do(func() {
+		d.logger.Warn(fmt.Sprintf("⚠️ DNS melalui proxy gagal (%v), fallback ke direct DNS", err))
+	})
+	return nil, nil
+}
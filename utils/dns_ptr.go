@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPTRSweepHostBits membatasi ukuran satu CIDR (IPv4 minimal /16, IPv6 minimal /112)
+const maxPTRSweepHostBits = 16
+
+// maxPTRSweepHosts membatasi jumlah alamat per sweep agar tidak meledak (misal IPv6 /64)
+const maxPTRSweepHosts = 1 << maxPTRSweepHostBits
+
+// PTRHost menyimpan hasil reverse lookup satu IP beserta status FCrDNS
+type PTRHost struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+	Confirmed []string `json:"fcrdns_confirmed,omitempty"`
+	FCrDNS    bool     `json:"fcrdns"`
+}
+
+// PTRSweepResult menyimpan inventaris IP → hostname dari reverse DNS sweep
+type PTRSweepResult struct {
+	Ranges    []string  `json:"ranges"`
+	Scanned   int       `json:"scanned"`
+	Hosts     []PTRHost `json:"hosts"`
+	Timestamp time.Time `json:"timestamp"`
+	Duration  string    `json:"duration"`
+}
+
+// SweepPTR melakukan PTR lookup concurrent untuk semua IP di CIDR yang diberikan
+func (d *DNSResolver) SweepPTR(cidrs []string, threads int) (*PTRSweepResult, error) {
+	startTime := time.Now()
+
+	ips, err := ExpandCIDRs(cidrs, maxPTRSweepHosts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PTRSweepResult{
+		Ranges:    cidrs,
+		Scanned:   len(ips),
+		Hosts:     make([]PTRHost, 0),
+		Timestamp: startTime,
+	}
+
+	if threads <= 0 {
+		threads = 1
+	}
+
+	if threads > len(ips) {
+		threads = len(ips)
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	// Worker pool sebanyak threads, bukan satu goroutine per alamat
+	queue := make(chan string)
+
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for addr := range queue {
+				names, err := d.ReverseLookup(addr)
+				if err != nil || len(names) == 0 {
+					continue
+				}
+
+				host := d.checkFCrDNS(addr, names)
+
+				mutex.Lock()
+				result.Hosts = append(result.Hosts, host)
+				mutex.Unlock()
+
+				d.logger.Debug(fmt.Sprintf("PTR %s → %s (FCrDNS: %v)", addr, strings.Join(host.Hostnames, ", "), host.FCrDNS))
+			}
+		}()
+	}
+
+	for _, ip := range ips {
+		queue <- ip
+	}
+	close(queue)
+
+	wg.Wait()
+
+	sort.Slice(result.Hosts, func(i, j int) bool {
+		return compareIP(result.Hosts[i].IP, result.Hosts[j].IP) < 0
+	})
+	result.Duration = time.Since(startTime).Round(time.Millisecond).String()
+
+	return result, nil
+}
+
+// checkFCrDNS me-resolve balik setiap hostname PTR dan mengecek apakah kembali ke IP asal
+func (d *DNSResolver) checkFCrDNS(ip string, names []string) PTRHost {
+	host := PTRHost{IP: ip}
+	target := net.ParseIP(ip)
+
+	for _, name := range names {
+		name = strings.TrimSuffix(strings.ToLower(name), ".")
+		host.Hostnames = append(host.Hostnames, name)
+
+		var forward []string
+		if target.To4() != nil {
+			forward, _ = d.LookupA(name)
+		} else {
+			forward, _ = d.LookupAAAA(name)
+		}
+
+		for _, addr := range forward {
+			if resolved := net.ParseIP(addr); resolved != nil && resolved.Equal(target) {
+				host.Confirmed = append(host.Confirmed, name)
+				break
+			}
+		}
+	}
+
+	host.FCrDNS = len(host.Confirmed) > 0
+	return host
+}
+
+// ExpandCIDRs mengubah daftar CIDR (atau IP tunggal) menjadi daftar IP
+func ExpandCIDRs(cidrs []string, limit int) ([]string, error) {
+	var ips []string
+	seen := make(map[string]bool)
+
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP or CIDR: %s", cidr)
+			}
+			if !seen[ip.String()] {
+				seen[ip.String()] = true
+				ips = append(ips, ip.String())
+			}
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %s: %v", cidr, err)
+		}
+
+		ones, bits := network.Mask.Size()
+		if bits-ones > maxPTRSweepHostBits {
+			return nil, fmt.Errorf("CIDR %s terlalu besar (prefix minimal /%d)", cidr, bits-maxPTRSweepHostBits)
+		}
+		if len(ips)+(1<<uint(bits-ones)) > limit {
+			return nil, fmt.Errorf("CIDR %s terlalu besar (maksimum %d alamat per sweep)", cidr, limit)
+		}
+
+		ip := make(net.IP, len(network.IP))
+		copy(ip, network.IP)
+		for ; network.Contains(ip); incrementIP(ip) {
+			if !seen[ip.String()] {
+				seen[ip.String()] = true
+				ips = append(ips, ip.String())
+			}
+		}
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no IP addresses to sweep")
+	}
+
+	return ips, nil
+}
+
+// incrementIP menaikkan IP satu alamat (in place)
+func incrementIP(ip net.IP) {
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i]++
+		if ip[i] != 0 {
+			return
+		}
+	}
+}
+
+// compareIP membandingkan dua IP secara numerik (IPv4 sebelum IPv6)
+func compareIP(a, b string) int {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if v4 := ipA.To4(); v4 != nil {
+		ipA = v4
+	}
+	if v4 := ipB.To4(); v4 != nil {
+		ipB = v4
+	}
+
+	if len(ipA) != len(ipB) {
+		return len(ipA) - len(ipB)
+	}
+	for i := range ipA {
+		if ipA[i] != ipB[i] {
+			return int(ipA[i]) - int(ipB[i])
+		}
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandCIDRs(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidrs   []string
+		limit   int
+		want    []string
+		wantErr string
+	}{
+		{"ip tunggal", []string{"192.0.2.1"}, 10, []string{"192.0.2.1"}, ""},
+		{"cidr /30", []string{"192.0.2.0/30"}, 10, []string{"192.0.2.0", "192.0.2.1", "192.0.2.2", "192.0.2.3"}, ""},
+		{"network dinormalisasi", []string{"192.0.2.5/31"}, 10, []string{"192.0.2.4", "192.0.2.5"}, ""},
+		{"dedupe", []string{"192.0.2.1", "192.0.2.0/31", " 192.0.2.1 ", ""}, 10, []string{"192.0.2.1", "192.0.2.0"}, ""},
+		{"ipv6 /126", []string{"2001:db8::/126"}, 10, []string{"2001:db8::", "2001:db8::1", "2001:db8::2", "2001:db8::3"}, ""},
+		{"ip tidak valid", []string{"192.0.2.256"}, 10, nil, "invalid IP or CIDR"},
+		{"cidr tidak valid", []string{"192.0.2.0/33"}, 10, nil, "invalid CIDR"},
+		{"ipv4 lebih besar dari /16", []string{"10.0.0.0/15"}, maxPTRSweepHosts, nil, "prefix minimal /16"},
+		{"ipv6 lebih besar dari /112", []string{"2001:db8::/64"}, maxPTRSweepHosts, nil, "prefix minimal /112"},
+		{"melebihi limit", []string{"192.0.2.0/29"}, 4, nil, "maksimum 4 alamat"},
+		{"limit kumulatif", []string{"192.0.2.0/31", "198.51.100.0/30"}, 5, nil, "maksimum 5 alamat"},
+		{"kosong", []string{" ", ""}, 10, nil, "no IP addresses"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandCIDRs(tt.cidrs, tt.limit)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandCIDRs(%v) = %v, want %v", tt.cidrs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandCIDRsMaxSweep(t *testing.T) {
+	ips, err := ExpandCIDRs([]string{"10.1.0.0/16"}, maxPTRSweepHosts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ips) != maxPTRSweepHosts || ips[0] != "10.1.0.0" || ips[len(ips)-1] != "10.1.255.255" {
+		t.Errorf("/16 menghasilkan %d alamat (%s - %s)", len(ips), ips[0], ips[len(ips)-1])
+	}
+}
@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// startDNSServer menjalankan handler di UDP dan TCP pada port yang sama lalu mengembalikan address-nya
+func startDNSServer(t *testing.T, handler dns.HandlerFunc) string {
+	t.Helper()
+
+	var listener net.Listener
+	var conn net.PacketConn
+	for attempt := 0; conn == nil; attempt++ {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen tcp: %v", err)
+		}
+		conn, err = net.ListenPacket("udp", listener.Addr().String())
+		if err != nil {
+			listener.Close()
+			if attempt >= 10 {
+				t.Fatalf("listen udp: %v", err)
+			}
+		}
+	}
+
+	for _, server := range []*dns.Server{
+		{PacketConn: conn, Handler: handler},
+		{Listener: listener, Handler: handler},
+	} {
+		server := server
+		go server.ActivateAndServe()
+		t.Cleanup(func() { server.Shutdown() })
+	}
+
+	return listener.Addr().String()
+}
+
+// newTestResolver membuat resolver yang hanya memakai servers lokal
+func newTestResolver(t *testing.T, servers ...string) *DNSResolver {
+	t.Helper()
+
+	resolver, err := NewDNSResolver(false, NewLogger(false, true))
+	if err != nil {
+		t.Fatalf("resolver: %v", err)
+	}
+	resolver.servers = servers
+	resolver.client.Timeout = 2 * time.Second
+	resolver.tcpClient.Timeout = 2 * time.Second
+	return resolver
+}
+
+// answerA membuat jawaban A 192.0.2.1 untuk query r
+func answerA(r *dns.Msg) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetReply(r)
+	msg.Answer = append(msg.Answer, &dns.A{
+		Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+		A:   net.ParseIP("192.0.2.1"),
+	})
+	return msg
+}
+
+// replyRcode membuat jawaban kosong dengan rcode tertentu
+func replyRcode(r *dns.Msg, rcode int) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetRcode(r, rcode)
+	return msg
+}
+
+func TestSendTruncatedRetriesTCP(t *testing.T) {
+	var udpQueries, tcpQueries int32
+	server := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		if w.RemoteAddr().Network() == "tcp" {
+			atomic.AddInt32(&tcpQueries, 1)
+			w.WriteMsg(answerA(r))
+			return
+		}
+
+		atomic.AddInt32(&udpQueries, 1)
+		msg := replyRcode(r, dns.RcodeSuccess)
+		msg.Truncated = true
+		w.WriteMsg(msg)
+	})
+
+	results, err := newTestResolver(t, server).LookupA("big.test")
+	if err != nil {
+		t.Fatalf("LookupA: %v", err)
+	}
+	if !reflect.DeepEqual(results, []string{"192.0.2.1"}) {
+		t.Errorf("results = %v, want jawaban dari TCP", results)
+	}
+	if udp, tcp := atomic.LoadInt32(&udpQueries), atomic.LoadInt32(&tcpQueries); udp != 1 || tcp != 1 {
+		t.Errorf("query UDP=%d TCP=%d, want 1 dan 1", udp, tcp)
+	}
+}
+
+func TestSendTruncatedKeepsUDPAnswerWhenTCPFails(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		msg := answerA(r)
+		msg.Truncated = true
+		w.WriteMsg(msg)
+	})}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	// Tanpa listener TCP: jawaban parsial UDP tetap dipakai
+	results, err := newTestResolver(t, conn.LocalAddr().String()).LookupA("big.test")
+	if err != nil {
+		t.Fatalf("LookupA: %v", err)
+	}
+	if !reflect.DeepEqual(results, []string{"192.0.2.1"}) {
+		t.Errorf("results = %v, want jawaban parsial UDP", results)
+	}
+}
+
+func TestSendRetriesServfail(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int32
+		wantQuery int32
+		wantErr   bool
+	}{
+		{"pulih setelah satu SERVFAIL", 1, 2, false},
+		{"pulih di retry terakhir", servfailRetries, servfailRetries + 1, false},
+		{"SERVFAIL terus", 100, servfailRetries + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries int32
+			server := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+				if atomic.AddInt32(&queries, 1) <= tt.failures {
+					w.WriteMsg(replyRcode(r, dns.RcodeServerFailure))
+					return
+				}
+				w.WriteMsg(answerA(r))
+			})
+
+			results, err := newTestResolver(t, server).LookupA("flaky.test")
+			if n := atomic.LoadInt32(&queries); n != tt.wantQuery {
+				t.Errorf("query = %d, want %d", n, tt.wantQuery)
+			}
+			if tt.wantErr {
+				var rcodeErr *RcodeError
+				if err == nil || !errors.As(err, &rcodeErr) || rcodeErr.Rcode != dns.RcodeServerFailure {
+					t.Errorf("err = %v, want RcodeError SERVFAIL", err)
+				}
+				return
+			}
+			if err != nil || len(results) != 1 {
+				t.Errorf("LookupA = %v, %v, want satu record", results, err)
+			}
+		})
+	}
+}
+
+func TestLookupClassicNegativeAnswerIsFinal(t *testing.T) {
+	tests := []struct {
+		name   string
+		rcode  int
+		wantNX bool
+	}{
+		{"NXDOMAIN", dns.RcodeNameError, true},
+		{"NODATA", dns.RcodeSuccess, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+				w.WriteMsg(replyRcode(r, tt.rcode))
+			})
+			var secondQueries int32
+			second := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+				atomic.AddInt32(&secondQueries, 1)
+				w.WriteMsg(answerA(r))
+			})
+
+			results, err := newTestResolver(t, first, second).LookupA("missing.test")
+			if IsNXDomain(err) != tt.wantNX {
+				t.Errorf("err = %v, want NXDOMAIN %v", err, tt.wantNX)
+			}
+			if !tt.wantNX && err != nil {
+				t.Errorf("err = %v, want nil untuk NODATA", err)
+			}
+			if len(results) != 0 {
+				t.Errorf("results = %v, want kosong", results)
+			}
+
+			// Beri waktu melewati resolverStagger agar server kedua pasti tidak di-query
+			time.Sleep(resolverStagger + 100*time.Millisecond)
+			if n := atomic.LoadInt32(&secondQueries); n != 0 {
+				t.Errorf("server kedua di-query %d kali, jawaban negatif harus final", n)
+			}
+		})
+	}
+}
+
+func TestLookupClassicFailover(t *testing.T) {
+	refused := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		w.WriteMsg(replyRcode(r, dns.RcodeRefused))
+	})
+	healthy := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		w.WriteMsg(answerA(r))
+	})
+
+	start := time.Now()
+	results, err := newTestResolver(t, refused, healthy).LookupA("www.test")
+	if err != nil || !reflect.DeepEqual(results, []string{"192.0.2.1"}) {
+		t.Fatalf("LookupA = %v, %v, want jawaban server kedua", results, err)
+	}
+	// Server gagal langsung diganti tanpa menunggu stagger
+	if elapsed := time.Since(start); elapsed >= resolverStagger {
+		t.Errorf("failover butuh %s, want < %s", elapsed, resolverStagger)
+	}
+}
+
+func TestLookupClassicRacesSlowServer(t *testing.T) {
+	release := make(chan struct{})
+	slow := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		<-release
+		w.WriteMsg(answerA(r))
+	})
+	fast := startDNSServer(t, func(w dns.ResponseWriter, r *dns.Msg) {
+		msg := answerA(r)
+		msg.Answer[0].(*dns.A).A = net.ParseIP("192.0.2.2")
+		w.WriteMsg(msg)
+	})
+	// Didaftarkan terakhir agar handler lambat selesai sebelum server di-shutdown
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	results, err := newTestResolver(t, slow, fast).LookupA("www.test")
+	if err != nil || !reflect.DeepEqual(results, []string{"192.0.2.2"}) {
+		t.Fatalf("LookupA = %v, %v, want jawaban server cepat", results, err)
+	}
+	if elapsed := time.Since(start); elapsed < resolverStagger || elapsed > resolverStagger+time.Second {
+		t.Errorf("server kedua menjawab setelah %s, want sekitar resolverStagger (%s)", elapsed, resolverStagger)
+	}
+}
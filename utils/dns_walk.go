@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultZoneWalkLimit adalah batas default jumlah nama/hash yang dikumpulkan
+const DefaultZoneWalkLimit = 1000
+
+// nsec3StallProbes adalah jumlah probe berturut-turut tanpa hash baru sebelum berhenti
+const nsec3StallProbes = 10
+
+// NSEC3Params menyimpan parameter NSEC3 sebuah zone
+type NSEC3Params struct {
+	Algorithm  uint8  `json:"algorithm"`
+	Iterations uint16 `json:"iterations"`
+	Salt       string `json:"salt,omitempty"`
+	OptOut     bool   `json:"opt_out"`
+}
+
+// ZoneWalkResult menyimpan hasil penilaian exposure NSEC/NSEC3 zone walking
+type ZoneWalkResult struct {
+	Zone         string       `json:"zone"`
+	Denial       string       `json:"denial_of_existence,omitempty"`
+	Names        []string     `json:"names,omitempty"`
+	Complete     bool         `json:"complete"`
+	LimitReached bool         `json:"limit_reached,omitempty"`
+	MinimalNSEC  bool         `json:"minimal_nsec,omitempty"`
+	NSEC3        *NSEC3Params `json:"nsec3,omitempty"`
+	Hashes       []string     `json:"nsec3_hashes,omitempty"`
+	Error        string       `json:"error,omitempty"`
+	Findings     []Finding    `json:"findings,omitempty"`
+	Timestamp    time.Time    `json:"timestamp"`
+	Duration     string       `json:"duration"`
+}
+
+// WalkZone menilai apakah NSEC memungkinkan enumerasi zone (walking sampai limit)
+// atau mengumpulkan hash dan parameter NSEC3 untuk dinilai terhadap RFC 9276
+func (d *DNSResolver) WalkZone(zone string, limit int) *ZoneWalkResult {
+	startTime := time.Now()
+	zone = strings.TrimSuffix(strings.ToLower(zone), ".")
+
+	if limit <= 0 {
+		limit = DefaultZoneWalkLimit
+	}
+
+	result := &ZoneWalkResult{
+		Zone:      zone,
+		Denial:    d.detectDenialType(zone),
+		Findings:  make([]Finding, 0),
+		Timestamp: startTime,
+	}
+
+	switch result.Denial {
+	case "NSEC":
+		d.walkNSEC(result, limit)
+	case "NSEC3":
+		d.collectNSEC3(result, limit)
+	default:
+		result.addFinding(SeverityInfo, "Tidak ada NSEC/NSEC3", "zone tidak ditandatangani DNSSEC atau resolver tidak mengirim denial of existence")
+	}
+
+	result.Duration = time.Since(startTime).Round(time.Millisecond).String()
+	return result
+}
+
+// walkNSEC mengikuti rantai NSEC dari apex sampai kembali ke apex atau limit
+func (d *DNSResolver) walkNSEC(result *ZoneWalkResult, limit int) {
+	apex := dns.Fqdn(result.Zone)
+	current := apex
+	seen := map[string]bool{apex: true}
+
+	for len(result.Names) < limit {
+		nsec, err := d.fetchNSEC(current)
+		if err != nil {
+			result.Error = err.Error()
+			break
+		}
+
+		// Minimal NSEC ("black lies"): next name selalu \000.<owner>, zone tidak bisa di-walk
+		next := strings.ToLower(nsec.NextDomain)
+		if strings.HasPrefix(next, `\000.`) {
+			result.MinimalNSEC = true
+			break
+		}
+
+		if next == apex {
+			result.Complete = true
+			break
+		}
+		if seen[next] || !dns.IsSubDomain(apex, next) {
+			result.Error = fmt.Sprintf("rantai NSEC tidak valid di %s → %s", current, next)
+			break
+		}
+
+		seen[next] = true
+		result.Names = append(result.Names, strings.TrimSuffix(next, "."))
+		current = next
+	}
+
+	result.LimitReached = !result.Complete && len(result.Names) >= limit
+
+	switch {
+	case result.MinimalNSEC:
+		result.addFinding(SeverityInfo, "Minimal NSEC (black lies)", "NSEC dihasilkan on-the-fly, zone walking tidak memungkinkan")
+	case result.Complete:
+		result.addFinding(SeverityHigh, "Zone dapat di-enumerasi penuh via NSEC",
+			fmt.Sprintf("%d nama ditemukan dengan mengikuti rantai NSEC", len(result.Names)))
+	case len(result.Names) > 0:
+		result.addFinding(SeverityHigh, "Zone walking NSEC berhasil",
+			fmt.Sprintf("%d nama ditemukan sebelum berhenti (limit %d)", len(result.Names), limit))
+	}
+}
+
+// fetchNSEC mengambil record NSEC milik sebuah nama. Jika query NSEC langsung tidak
+// dijawab, nama \000.<name> di-query karena NSEC pemilik name yang menutupinya
+func (d *DNSResolver) fetchNSEC(name string) (*dns.NSEC, error) {
+	if resp, err := d.queryDNSSEC(name, dns.TypeNSEC); err == nil {
+		for _, rr := range resp.Answer {
+			if nsec, ok := rr.(*dns.NSEC); ok && strings.EqualFold(nsec.Hdr.Name, name) {
+				return nsec, nil
+			}
+		}
+	}
+
+	resp, err := d.queryDNSSEC(`\000.`+name, dns.TypeA)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rr := range resp.Ns {
+		if nsec, ok := rr.(*dns.NSEC); ok && strings.EqualFold(nsec.Hdr.Name, name) {
+			return nsec, nil
+		}
+	}
+	return nil, fmt.Errorf("NSEC untuk %s tidak ditemukan", name)
+}
+
+// collectNSEC3 mengumpulkan hash NSEC3 dari jawaban NXDOMAIN dan menilai parameternya
+func (d *DNSResolver) collectNSEC3(result *ZoneWalkResult, limit int) {
+	apex := dns.Fqdn(result.Zone)
+
+	if resp, err := d.queryDNSSEC(apex, dns.TypeNSEC3PARAM); err == nil {
+		for _, rr := range resp.Answer {
+			if param, ok := rr.(*dns.NSEC3PARAM); ok {
+				result.NSEC3 = &NSEC3Params{
+					Algorithm:  param.Hash,
+					Iterations: param.Iterations,
+					Salt:       strings.TrimPrefix(param.Salt, "-"),
+				}
+				break
+			}
+		}
+	}
+
+	hashes := make(map[string]bool)
+	stall := 0
+
+	for probe := 0; probe < limit && len(hashes) < limit && stall < nsec3StallProbes; probe++ {
+		resp, err := d.queryDNSSEC(randomLabel()+"."+result.Zone, dns.TypeA)
+		if err != nil {
+			result.Error = err.Error()
+			break
+		}
+
+		found := 0
+		for _, rr := range resp.Ns {
+			nsec3, ok := rr.(*dns.NSEC3)
+			if !ok {
+				continue
+			}
+
+			if result.NSEC3 == nil {
+				result.NSEC3 = &NSEC3Params{
+					Algorithm:  nsec3.Hash,
+					Iterations: nsec3.Iterations,
+					Salt:       strings.TrimPrefix(nsec3.Salt, "-"),
+				}
+			}
+			if nsec3.Flags&1 == 1 {
+				result.NSEC3.OptOut = true
+			}
+
+			owner := strings.ToLower(strings.SplitN(nsec3.Hdr.Name, ".", 2)[0])
+			for _, hash := range []string{owner, strings.ToLower(nsec3.NextDomain)} {
+				if !hashes[hash] {
+					hashes[hash] = true
+					found++
+				}
+			}
+		}
+
+		if found == 0 {
+			stall++
+		} else {
+			stall = 0
+		}
+	}
+
+	for hash := range hashes {
+		result.Hashes = append(result.Hashes, hash)
+	}
+	sort.Strings(result.Hashes)
+	result.LimitReached = len(result.Hashes) >= limit
+
+	if len(result.Hashes) > 0 {
+		result.addFinding(SeverityInfo, "Hash NSEC3 terkumpul",
+			fmt.Sprintf("%d hash dapat di-crack offline untuk memulihkan nama", len(result.Hashes)))
+	}
+
+	d.assessNSEC3Params(result)
+}
+
+// assessNSEC3Params menilai parameter NSEC3 terhadap rekomendasi RFC 9276
+func (d *DNSResolver) assessNSEC3Params(result *ZoneWalkResult) {
+	params := result.NSEC3
+	if params == nil {
+		return
+	}
+
+	switch {
+	case params.Iterations > 100:
+		result.addFinding(SeverityHigh, "Iterasi NSEC3 berlebihan",
+			fmt.Sprintf("%d iterasi: validating resolver dapat memperlakukan zone sebagai insecure atau SERVFAIL (RFC 9276 §3.2)", params.Iterations))
+	case params.Iterations > 0:
+		result.addFinding(SeverityMedium, "Iterasi NSEC3 bukan 0",
+			fmt.Sprintf("%d iterasi tidak menambah proteksi berarti, RFC 9276 merekomendasikan 0", params.Iterations))
+	}
+
+	if params.Salt != "" {
+		result.addFinding(SeverityLow, "NSEC3 memakai salt",
+			fmt.Sprintf("salt %s: RFC 9276 merekomendasikan salt kosong", params.Salt))
+	}
+
+	if params.OptOut {
+		result.addFinding(SeverityLow, "NSEC3 opt-out aktif",
+			"opt-out hanya disarankan untuk zone delegasi yang sangat besar (RFC 9276 §3.1)")
+	}
+
+	if params.Algorithm != dns.SHA1 {
+		result.addFinding(SeverityMedium, "Algoritma hash NSEC3 tidak dikenal",
+			fmt.Sprintf("algoritma %d, hanya SHA-1 (1) yang didefinisikan", params.Algorithm))
+	}
+}
+
+// addFinding menambahkan finding kategori dns
+func (r *ZoneWalkResult) addFinding(severity, title, detail string) {
+	r.Findings = append(r.Findings, NewFinding(severity, "dns", title, detail))
+}
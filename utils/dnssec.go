@@ -0,0 +1,535 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Status hasil validasi DNSSEC
+const (
+	DNSSECSecure        = "secure"
+	DNSSECInsecure      = "insecure"
+	DNSSECBogus         = "bogus"
+	DNSSECIndeterminate = "indeterminate"
+)
+
+// rootTrustAnchors adalah DS dari root KSK-2017 dan KSK-2024 (IANA)
+var rootTrustAnchors = []string{
+	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	". IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+// DNSSECKey menyimpan detail satu DNSKEY
+type DNSSECKey struct {
+	KeyTag    uint16 `json:"key_tag"`
+	Role      string `json:"role"`
+	Algorithm string `json:"algorithm"`
+	KeySize   int    `json:"key_size,omitempty"`
+}
+
+// DNSSECLink menyimpan status satu zone dalam chain of trust
+type DNSSECLink struct {
+	Zone   string      `json:"zone"`
+	Status string      `json:"status"`
+	DS     []string    `json:"ds,omitempty"`
+	Keys   []DNSSECKey `json:"keys,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// DNSSECResult menyimpan hasil validasi chain of trust untuk satu domain
+type DNSSECResult struct {
+	Domain      string       `json:"domain"`
+	Status      string       `json:"status"`
+	FailingLink string       `json:"failing_link,omitempty"`
+	Reason      string       `json:"reason,omitempty"`
+	Denial      string       `json:"denial_of_existence,omitempty"`
+	Chain       []DNSSECLink `json:"chain"`
+	Timestamp   time.Time    `json:"timestamp"`
+}
+
+// LoadTrustAnchor membaca trust anchor (DS atau DNSKEY root) dari file zone format
+func (d *DNSResolver) LoadTrustAnchor(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var anchors []*dns.DS
+	parser := dns.NewZoneParser(file, ".", filename)
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		switch v := rr.(type) {
+		case *dns.DS:
+			anchors = append(anchors, v)
+		case *dns.DNSKEY:
+			if ds := v.ToDS(dns.SHA256); ds != nil {
+				anchors = append(anchors, ds)
+			}
+		}
+	}
+
+	if err := parser.Err(); err != nil {
+		return fmt.Errorf("failed to parse trust anchor: %v", err)
+	}
+	if len(anchors) == 0 {
+		return fmt.Errorf("no DS or DNSKEY records in %s", filename)
+	}
+
+	d.trustAnchors = anchors
+	d.logger.Info(fmt.Sprintf("🔐 Trust anchor loaded: %d key(s) dari %s", len(anchors), filename))
+	return nil
+}
+
+// ValidateDNSSEC memvalidasi chain of trust dari trust anchor sampai domain
+func (d *DNSResolver) ValidateDNSSEC(domain string) *DNSSECResult {
+	result := &DNSSECResult{
+		Domain:    strings.TrimSuffix(domain, "."),
+		Chain:     make([]DNSSECLink, 0),
+		Timestamp: time.Now(),
+	}
+
+	zone, zoneKeys, ok := d.walkChain(domain, result)
+	if !ok {
+		return result
+	}
+
+	if !d.validateAnswer(domain, zone, zoneKeys, result) {
+		return result
+	}
+
+	result.Status = DNSSECSecure
+	result.Denial = d.detectDenialType(zone)
+
+	return result
+}
+
+// walkChain memvalidasi DS/DNSKEY dari root sampai zone terdalam yang memuat domain.
+// Mengembalikan zone dan DNSKEY-nya; jika gagal, result sudah ditandai lewat fail
+func (d *DNSResolver) walkChain(domain string, result *DNSSECResult) (string, []*dns.DNSKEY, bool) {
+	trustedDS := d.getTrustAnchors()
+	var zoneKeys []*dns.DNSKEY
+	zone := ""
+
+	for _, name := range chainNames(domain) {
+		link := DNSSECLink{Zone: name}
+
+		if name != "." {
+			resp, err := d.queryDNSSEC(name, dns.TypeDS)
+			if err != nil {
+				result.fail(DNSSECIndeterminate, link, fmt.Sprintf("query DS gagal: %v", err))
+				return "", nil, false
+			}
+
+			dsSet, sigs := splitSigned(resp.Answer, dns.TypeDS)
+			if len(dsSet) == 0 {
+				// Bukan zone cut: lanjut dengan keys dari zone parent
+				if !d.isZoneApex(name) {
+					continue
+				}
+
+				// DS yang dihapus di jalan tidak boleh terlihat seperti delegasi insecure
+				if err := verifyDenial(resp, name, dns.TypeDS, zone, zoneKeys); err != nil {
+					result.fail(DNSSECBogus, link, fmt.Sprintf("delegasi tanpa DS dan tanpa bukti denial yang valid: %v", err))
+				} else {
+					result.fail(DNSSECInsecure, link, "delegasi tanpa DS (terbukti oleh NSEC/NSEC3)")
+				}
+				return "", nil, false
+			}
+
+			if err := verifyRRSet(dsSet, sigs, zone, zoneKeys); err != nil {
+				result.fail(DNSSECBogus, link, fmt.Sprintf("RRSIG DS tidak valid: %v", err))
+				return "", nil, false
+			}
+
+			trustedDS = nil
+			for _, rr := range dsSet {
+				ds := rr.(*dns.DS)
+				trustedDS = append(trustedDS, ds)
+				link.DS = append(link.DS, fmt.Sprintf("%d %s %d", ds.KeyTag, dns.AlgorithmToString[ds.Algorithm], ds.DigestType))
+			}
+		}
+
+		resp, err := d.queryDNSSEC(name, dns.TypeDNSKEY)
+		if err != nil {
+			result.fail(DNSSECIndeterminate, link, fmt.Sprintf("query DNSKEY gagal: %v", err))
+			return "", nil, false
+		}
+
+		keySet, sigs := splitSigned(resp.Answer, dns.TypeDNSKEY)
+		if len(keySet) == 0 {
+			result.fail(DNSSECBogus, link, "DS ada tetapi DNSKEY tidak ditemukan")
+			return "", nil, false
+		}
+
+		var keys, entryKeys []*dns.DNSKEY
+		for _, rr := range keySet {
+			key := rr.(*dns.DNSKEY)
+			keys = append(keys, key)
+			link.Keys = append(link.Keys, describeDNSKEY(key))
+			if matchesDS(key, trustedDS) {
+				entryKeys = append(entryKeys, key)
+			}
+		}
+
+		if len(entryKeys) == 0 {
+			result.fail(DNSSECBogus, link, "tidak ada DNSKEY yang cocok dengan DS/trust anchor")
+			return "", nil, false
+		}
+
+		if err := verifyRRSet(keySet, sigs, name, entryKeys); err != nil {
+			result.fail(DNSSECBogus, link, fmt.Sprintf("RRSIG DNSKEY tidak valid: %v", err))
+			return "", nil, false
+		}
+
+		link.Status = DNSSECSecure
+		result.Chain = append(result.Chain, link)
+		zone, zoneKeys = name, keys
+	}
+
+	return zone, zoneKeys, true
+}
+
+// validateAnswer memvalidasi RRset CNAME dan A dari jawaban akhir. Setiap RRset diverifikasi
+// dengan DNSKEY zone signer-nya, sehingga CNAME ke zone lain (misal CDN) divalidasi lewat
+// chain of trust zone tersebut. Jawaban NODATA/NXDOMAIN harus disertai bukti NSEC/NSEC3
+func (d *DNSResolver) validateAnswer(domain, zone string, zoneKeys []*dns.DNSKEY, result *DNSSECResult) bool {
+	resp, err := d.queryDNSSEC(dns.Fqdn(domain), dns.TypeA)
+	if err != nil {
+		result.fail(DNSSECIndeterminate, DNSSECLink{Zone: dns.Fqdn(domain)}, fmt.Sprintf("query A gagal: %v", err))
+		return false
+	}
+
+	zones := map[string][]*dns.DNSKEY{strings.ToLower(zone): zoneKeys}
+
+	for _, qtype := range []uint16{dns.TypeCNAME, dns.TypeA} {
+		records, sigs := splitSigned(resp.Answer, qtype)
+
+		// Kelompokkan per owner name karena RRSIG dihitung per RRset
+		byOwner := make(map[string][]dns.RR)
+		var owners []string
+		for _, rr := range records {
+			owner := strings.ToLower(rr.Header().Name)
+			if _, ok := byOwner[owner]; !ok {
+				owners = append(owners, owner)
+			}
+			byOwner[owner] = append(byOwner[owner], rr)
+		}
+
+		for _, owner := range owners {
+			var ownerSigs []*dns.RRSIG
+			for _, sig := range sigs {
+				if strings.EqualFold(sig.Hdr.Name, owner) {
+					ownerSigs = append(ownerSigs, sig)
+				}
+			}
+
+			signer, keys, ok := d.signerKeys(owner, zone, ownerSigs, zones, qtype, result)
+			if !ok {
+				return false
+			}
+
+			if err := verifyRRSet(byOwner[owner], ownerSigs, signer, keys); err != nil {
+				link := DNSSECLink{Zone: dns.Fqdn(owner)}
+				result.fail(DNSSECBogus, link, fmt.Sprintf("RRSIG %s tidak valid: %v", dns.TypeToString[qtype], err))
+				return false
+			}
+		}
+	}
+
+	// Nama terakhir di chain CNAME tanpa A berarti NODATA/NXDOMAIN yang harus dibuktikan
+	final := finalCNAMETarget(resp.Answer, dns.Fqdn(domain))
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == dns.TypeA && strings.EqualFold(rr.Header().Name, final) {
+			return true
+		}
+	}
+
+	_, denialSigs := splitSigned(resp.Ns, dns.TypeNSEC)
+	_, nsec3Sigs := splitSigned(resp.Ns, dns.TypeNSEC3)
+	signer, keys, ok := d.signerKeys(final, zone, append(denialSigs, nsec3Sigs...), zones, dns.TypeNSEC, result)
+	if !ok {
+		return false
+	}
+
+	if err := verifyDenial(resp, final, dns.TypeA, signer, keys); err != nil {
+		kind := "NODATA"
+		if resp.Rcode == dns.RcodeNameError {
+			kind = "NXDOMAIN"
+		}
+		link := DNSSECLink{Zone: final}
+		result.fail(DNSSECBogus, link, fmt.Sprintf("jawaban %s tanpa bukti denial yang valid: %v", kind, err))
+		return false
+	}
+
+	return true
+}
+
+// signerKeys menentukan zone signer untuk owner dari RRSIG dan mengembalikan DNSKEY-nya.
+// Zone yang belum dikenal divalidasi lewat walkSignerChain dan disimpan di zones
+func (d *DNSResolver) signerKeys(owner, zone string, sigs []*dns.RRSIG, zones map[string][]*dns.DNSKEY, qtype uint16, result *DNSSECResult) (string, []*dns.DNSKEY, bool) {
+	// Zone signer dari RRSIG; tanpa RRSIG, chain owner menentukan apakah zone-nya insecure
+	signer := strings.ToLower(owner)
+	if len(sigs) > 0 {
+		signer = strings.ToLower(sigs[0].SignerName)
+	} else if isSubdomainOf(owner, zone) {
+		signer = strings.ToLower(zone)
+	}
+
+	// Signer hanya berwenang atas nama di dalam zone-nya sendiri
+	if !isSubdomainOf(owner, signer) {
+		link := DNSSECLink{Zone: dns.Fqdn(owner)}
+		result.fail(DNSSECBogus, link, fmt.Sprintf("RRSIG %s ditandatangani zone lain (%s)", dns.TypeToString[qtype], signer))
+		return "", nil, false
+	}
+
+	keys, ok := zones[signer]
+	if !ok {
+		var signerZone string
+		if signerZone, keys, ok = d.walkSignerChain(signer, result); !ok {
+			return "", nil, false
+		}
+		signer = strings.ToLower(signerZone)
+		zones[signer] = keys
+	}
+
+	return signer, keys, true
+}
+
+// finalCNAMETarget mengikuti chain CNAME di answer mulai dari name
+func finalCNAMETarget(answer []dns.RR, name string) string {
+	for range answer {
+		next := ""
+		for _, rr := range answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, name) {
+				next = cname.Target
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		name = next
+	}
+	return strings.ToLower(dns.Fqdn(name))
+}
+
+// walkSignerChain memvalidasi chain of trust zone target CNAME dan menambahkan link zone
+// tersebut ke result. Status insecure/bogus zone target menjadi status akhir result
+func (d *DNSResolver) walkSignerChain(name string, result *DNSSECResult) (string, []*dns.DNSKEY, bool) {
+	sub := &DNSSECResult{Domain: strings.TrimSuffix(name, ".")}
+
+	zone, keys, ok := d.walkChain(name, sub)
+	if !ok {
+		link := sub.Chain[len(sub.Chain)-1]
+		result.fail(sub.Status, link, fmt.Sprintf("target CNAME %s: %s", sub.Domain, sub.Reason))
+		return "", nil, false
+	}
+
+	if len(sub.Chain) > 0 {
+		result.Chain = append(result.Chain, sub.Chain[len(sub.Chain)-1])
+	}
+	return zone, keys, true
+}
+
+// fail menandai hasil validasi dengan status dan link yang gagal
+func (r *DNSSECResult) fail(status string, link DNSSECLink, reason string) *DNSSECResult {
+	link.Status = status
+	link.Error = reason
+	r.Chain = append(r.Chain, link)
+
+	r.Status = status
+	r.FailingLink = link.Zone
+	r.Reason = reason
+	return r
+}
+
+// getTrustAnchors mengembalikan trust anchor yang dikonfigurasi atau root default
+func (d *DNSResolver) getTrustAnchors() []*dns.DS {
+	if len(d.trustAnchors) > 0 {
+		return d.trustAnchors
+	}
+
+	var anchors []*dns.DS
+	for _, line := range rootTrustAnchors {
+		if rr, err := dns.NewRR(line); err == nil {
+			anchors = append(anchors, rr.(*dns.DS))
+		}
+	}
+	return anchors
+}
+
+// queryDNSSEC melakukan query dengan DO bit dan CD bit agar RRSIG ikut dikirim
+func (d *DNSResolver) queryDNSSEC(name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+	msg.CheckingDisabled = true
+	msg.SetEdns0(d.ednsSize, true)
+
+	return d.exchange(msg)
+}
+
+// isZoneApex mengecek apakah nama adalah apex sebuah zone (punya SOA sendiri)
+func (d *DNSResolver) isZoneApex(name string) bool {
+	resp, err := d.queryDNSSEC(name, dns.TypeSOA)
+	if err != nil {
+		return false
+	}
+
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dns.SOA); ok && strings.EqualFold(soa.Hdr.Name, dns.Fqdn(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectDenialType mendeteksi apakah zone memakai NSEC atau NSEC3
+func (d *DNSResolver) detectDenialType(zone string) string {
+	if zone == "" {
+		return ""
+	}
+
+	resp, err := d.queryDNSSEC(randomLabel()+"."+zone, dns.TypeA)
+	if err != nil {
+		return ""
+	}
+
+	for _, rr := range resp.Ns {
+		switch rr.Header().Rrtype {
+		case dns.TypeNSEC3:
+			return "NSEC3"
+		case dns.TypeNSEC:
+			return "NSEC"
+		}
+	}
+	return ""
+}
+
+// splitSigned memisahkan records dengan tipe tertentu dan RRSIG yang menutupinya
+func splitSigned(rrs []dns.RR, qtype uint16) ([]dns.RR, []*dns.RRSIG) {
+	var records []dns.RR
+	var sigs []*dns.RRSIG
+
+	for _, rr := range rrs {
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			if sig.TypeCovered == qtype {
+				sigs = append(sigs, sig)
+			}
+			continue
+		}
+		if rr.Header().Rrtype == qtype {
+			records = append(records, rr)
+		}
+	}
+
+	return records, sigs
+}
+
+// verifyRRSet memverifikasi RRset dengan salah satu RRSIG dari signer
+func verifyRRSet(rrset []dns.RR, sigs []*dns.RRSIG, signer string, keys []*dns.DNSKEY) error {
+	if len(sigs) == 0 {
+		return fmt.Errorf("RRSIG tidak ditemukan")
+	}
+
+	lastErr := fmt.Errorf("tidak ada DNSKEY yang cocok dengan RRSIG")
+	for _, sig := range sigs {
+		if !strings.EqualFold(sig.SignerName, signer) {
+			lastErr = fmt.Errorf("signer %s tidak sesuai (expected %s)", sig.SignerName, signer)
+			continue
+		}
+
+		if !sig.ValidityPeriod(time.Now()) {
+			lastErr = fmt.Errorf("RRSIG key tag %d di luar masa berlaku", sig.KeyTag)
+			continue
+		}
+
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+				continue
+			}
+			if err := sig.Verify(key, rrset); err != nil {
+				lastErr = fmt.Errorf("key tag %d: %v", sig.KeyTag, err)
+				continue
+			}
+			return nil
+		}
+	}
+
+	return lastErr
+}
+
+// matchesDS mengecek apakah DNSKEY cocok dengan salah satu DS
+func matchesDS(key *dns.DNSKEY, dsSet []*dns.DS) bool {
+	for _, ds := range dsSet {
+		if ds.KeyTag != key.KeyTag() || ds.Algorithm != key.Algorithm {
+			continue
+		}
+		if computed := key.ToDS(ds.DigestType); computed != nil && strings.EqualFold(computed.Digest, ds.Digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeDNSKEY mengekstrak detail algoritma dan ukuran key
+func describeDNSKEY(key *dns.DNSKEY) DNSSECKey {
+	role := "ZSK"
+	if key.Flags&dns.SEP != 0 {
+		role = "KSK"
+	}
+
+	return DNSSECKey{
+		KeyTag:    key.KeyTag(),
+		Role:      role,
+		Algorithm: dns.AlgorithmToString[key.Algorithm],
+		KeySize:   dnskeySize(key),
+	}
+}
+
+// dnskeySize menghitung ukuran key dalam bit
+func dnskeySize(key *dns.DNSKEY) int {
+	switch key.Algorithm {
+	case dns.ECDSAP256SHA256, dns.ED25519:
+		return 256
+	case dns.ECDSAP384SHA384:
+		return 384
+	case dns.ED448:
+		return 456
+	case dns.RSASHA1, dns.RSASHA1NSEC3SHA1, dns.RSASHA256, dns.RSASHA512:
+		// Format RFC 3110: panjang exponent, exponent, lalu modulus
+		raw, err := base64.StdEncoding.DecodeString(key.PublicKey)
+		if err != nil || len(raw) < 3 {
+			return 0
+		}
+
+		expLen, offset := int(raw[0]), 1
+		if expLen == 0 {
+			expLen, offset = int(raw[1])<<8|int(raw[2]), 3
+		}
+		if offset+expLen >= len(raw) {
+			return 0
+		}
+
+		return new(big.Int).SetBytes(raw[offset+expLen:]).BitLen()
+	default:
+		return 0
+	}
+}
+
+// chainNames menghasilkan daftar nama dari root sampai domain
+func chainNames(domain string) []string {
+	labels := dns.SplitDomainName(dns.Fqdn(domain))
+	names := []string{"."}
+
+	for i := len(labels) - 1; i >= 0; i-- {
+		names = append(names, dns.Fqdn(strings.Join(labels[i:], ".")))
+	}
+	return names
+}
@@ -0,0 +1,302 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// nsec3OptOut adalah flag opt-out pada NSEC3 (RFC 5155 §3.1.2)
+const nsec3OptOut = 1
+
+// denialProof berisi NSEC/NSEC3 dari authority section yang signature-nya sudah valid
+type denialProof struct {
+	nsec  []*dns.NSEC
+	nsec3 []*dns.NSEC3
+}
+
+// verifyDenial memverifikasi authenticated denial of existence untuk qname/qtype:
+// hanya NSEC/NSEC3 yang RRSIG-nya valid untuk signer yang dipakai sebagai bukti.
+// Query DS NOERROR dibuktikan sebagai delegasi tanpa DS (RFC 4035 §5.2, RFC 5155 §8.6),
+// NXDOMAIN sebagai nama tidak ada, selain itu sebagai NODATA
+func verifyDenial(resp *dns.Msg, qname string, qtype uint16, signer string, keys []*dns.DNSKEY) error {
+	proof := verifiedDenialRecords(resp, signer, keys)
+	if len(proof.nsec) == 0 && len(proof.nsec3) == 0 {
+		return fmt.Errorf("tidak ada NSEC/NSEC3 bertanda tangan valid dari %s", signer)
+	}
+
+	switch {
+	case resp.Rcode == dns.RcodeNameError:
+		return proof.proveNXDomain(qname)
+	case qtype == dns.TypeDS:
+		return proof.proveNoDS(qname)
+	default:
+		return proof.proveNoData(qname, qtype)
+	}
+}
+
+// verifiedDenialRecords mengambil NSEC/NSEC3 di authority section yang RRSIG-nya valid
+func verifiedDenialRecords(resp *dns.Msg, signer string, keys []*dns.DNSKEY) *denialProof {
+	proof := &denialProof{}
+
+	for _, qtype := range []uint16{dns.TypeNSEC, dns.TypeNSEC3} {
+		records, sigs := splitSigned(resp.Ns, qtype)
+
+		// Kelompokkan per owner name karena RRSIG dihitung per RRset
+		byOwner := make(map[string][]dns.RR)
+		var owners []string
+		for _, rr := range records {
+			owner := strings.ToLower(rr.Header().Name)
+			if _, ok := byOwner[owner]; !ok {
+				owners = append(owners, owner)
+			}
+			byOwner[owner] = append(byOwner[owner], rr)
+		}
+
+		for _, owner := range owners {
+			var ownerSigs []*dns.RRSIG
+			for _, sig := range sigs {
+				if strings.EqualFold(sig.Hdr.Name, owner) {
+					ownerSigs = append(ownerSigs, sig)
+				}
+			}
+			if verifyRRSet(byOwner[owner], ownerSigs, signer, keys) != nil {
+				continue
+			}
+
+			for _, rr := range byOwner[owner] {
+				switch v := rr.(type) {
+				case *dns.NSEC:
+					proof.nsec = append(proof.nsec, v)
+				case *dns.NSEC3:
+					proof.nsec3 = append(proof.nsec3, v)
+				}
+			}
+		}
+	}
+
+	return proof
+}
+
+// proveNoDS membuktikan delegasi name tidak punya DS: NSEC/NSEC3 milik name dari zone
+// parent (bit NS ada, bit DS dan SOA kosong), atau next closer name di span NSEC3 opt-out
+func (p *denialProof) proveNoDS(name string) error {
+	for _, nsec := range p.nsec {
+		if strings.EqualFold(nsec.Hdr.Name, dns.Fqdn(name)) {
+			return checkDelegationBitmap("NSEC", name, nsec.TypeBitMap)
+		}
+	}
+
+	if len(p.nsec3) == 0 {
+		return fmt.Errorf("tidak ada NSEC untuk delegasi %s", name)
+	}
+
+	if nsec3 := p.matchingNSEC3(name); nsec3 != nil {
+		return checkDelegationBitmap("NSEC3", name, nsec3.TypeBitMap)
+	}
+
+	// Tanpa NSEC3 yang match, delegasi hanya boleh berada di span opt-out
+	_, nextCloser, err := p.closestEncloser(name)
+	if err != nil {
+		return err
+	}
+	if covering := p.coveringNSEC3(nextCloser); covering.Flags&nsec3OptOut == 0 {
+		return fmt.Errorf("NSEC3 yang mencakup %s bukan opt-out", nextCloser)
+	}
+	return nil
+}
+
+// proveNoData membuktikan name ada tetapi tidak punya record qtype (termasuk CNAME)
+func (p *denialProof) proveNoData(name string, qtype uint16) error {
+	for _, nsec := range p.nsec {
+		if strings.EqualFold(nsec.Hdr.Name, dns.Fqdn(name)) {
+			return checkNoDataBitmap("NSEC", name, qtype, nsec.TypeBitMap)
+		}
+
+		// Empty non-terminal: NSEC mencakup name dan next name berada di bawah name
+		if nsecCovers(nsec, name) && isSubdomainOf(nsec.NextDomain, name) {
+			return nil
+		}
+	}
+
+	if nsec3 := p.matchingNSEC3(name); nsec3 != nil {
+		return checkNoDataBitmap("NSEC3", name, qtype, nsec3.TypeBitMap)
+	}
+
+	return fmt.Errorf("tidak ada NSEC/NSEC3 yang match %s", name)
+}
+
+// proveNXDomain membuktikan name tidak ada: name dan wildcard di closest encloser
+// sama-sama dicakup NSEC (RFC 4035 §5.4) atau closest encloser proof NSEC3 (RFC 5155 §8.4)
+func (p *denialProof) proveNXDomain(name string) error {
+	name = dns.Fqdn(name)
+
+	for _, nsec := range p.nsec {
+		if !nsecCovers(nsec, name) {
+			continue
+		}
+
+		// Closest encloser adalah ancestor name terpanjang milik owner atau next name
+		labels := dns.CompareDomainName(name, nsec.Hdr.Name)
+		if next := dns.CompareDomainName(name, nsec.NextDomain); next > labels {
+			labels = next
+		}
+		if labels >= dns.CountLabel(name) {
+			return fmt.Errorf("NSEC menyatakan %s ada (empty non-terminal)", name)
+		}
+		wildcard := wildcardName(ancestorName(name, labels))
+
+		for _, candidate := range p.nsec {
+			if nsecCovers(candidate, wildcard) {
+				return nil
+			}
+		}
+		return fmt.Errorf("wildcard %s tidak dicakup NSEC", wildcard)
+	}
+
+	if len(p.nsec3) == 0 {
+		return fmt.Errorf("tidak ada NSEC yang mencakup %s", name)
+	}
+
+	encloser, nextCloser, err := p.closestEncloser(name)
+	if err != nil {
+		return err
+	}
+	if nextCloser == "" {
+		return fmt.Errorf("NSEC3 menyatakan %s ada", name)
+	}
+
+	wildcard := wildcardName(encloser)
+	if p.coveringNSEC3(wildcard) == nil {
+		return fmt.Errorf("wildcard %s tidak dicakup NSEC3", wildcard)
+	}
+	return nil
+}
+
+// closestEncloser mencari ancestor terdekat name yang punya NSEC3 match dan next closer
+// name-nya (satu label lebih panjang) yang dicakup NSEC3 (RFC 5155 §8.3). Jika name
+// sendiri match, nextCloser kosong
+func (p *denialProof) closestEncloser(name string) (string, string, error) {
+	candidate := dns.Fqdn(strings.ToLower(name))
+	nextCloser := ""
+
+	for {
+		if p.matchingNSEC3(candidate) != nil {
+			if nextCloser != "" && p.coveringNSEC3(nextCloser) == nil {
+				return "", "", fmt.Errorf("next closer %s tidak dicakup NSEC3", nextCloser)
+			}
+			return candidate, nextCloser, nil
+		}
+
+		if candidate == "." {
+			return "", "", fmt.Errorf("closest encloser untuk %s tidak ditemukan", name)
+		}
+		nextCloser = candidate
+		candidate = parentName(candidate)
+	}
+}
+
+// matchingNSEC3 mengembalikan NSEC3 yang hash owner-nya sama dengan hash name
+func (p *denialProof) matchingNSEC3(name string) *dns.NSEC3 {
+	for _, nsec3 := range p.nsec3 {
+		if nsec3.Match(dns.Fqdn(name)) {
+			return nsec3
+		}
+	}
+	return nil
+}
+
+// coveringNSEC3 mengembalikan NSEC3 yang span hash-nya mencakup name
+func (p *denialProof) coveringNSEC3(name string) *dns.NSEC3 {
+	for _, nsec3 := range p.nsec3 {
+		if nsec3.Cover(dns.Fqdn(name)) {
+			return nsec3
+		}
+	}
+	return nil
+}
+
+// checkDelegationBitmap memastikan bitmap milik delegasi tanpa DS di zone parent
+func checkDelegationBitmap(kind, name string, bitmap []uint16) error {
+	switch {
+	case hasType(bitmap, dns.TypeDS):
+		return fmt.Errorf("%s %s menyatakan DS ada", kind, name)
+	case hasType(bitmap, dns.TypeSOA):
+		return fmt.Errorf("%s %s berasal dari zone child, bukan parent", kind, name)
+	case !hasType(bitmap, dns.TypeNS):
+		return fmt.Errorf("%s %s bukan delegasi (bit NS kosong)", kind, name)
+	}
+	return nil
+}
+
+// checkNoDataBitmap memastikan bitmap tidak memuat qtype maupun CNAME
+func checkNoDataBitmap(kind, name string, qtype uint16, bitmap []uint16) error {
+	for _, t := range []uint16{qtype, dns.TypeCNAME} {
+		if hasType(bitmap, t) {
+			return fmt.Errorf("%s %s menyatakan %s ada", kind, name, dns.TypeToString[t])
+		}
+	}
+	return nil
+}
+
+// hasType mengecek apakah type bitmap NSEC/NSEC3 memuat qtype
+func hasType(bitmap []uint16, qtype uint16) bool {
+	for _, t := range bitmap {
+		if t == qtype {
+			return true
+		}
+	}
+	return false
+}
+
+// nsecCovers mengecek apakah name berada di antara owner dan next name NSEC
+// (urutan kanonik). NSEC terakhir di zone menunjuk kembali ke apex
+func nsecCovers(nsec *dns.NSEC, name string) bool {
+	owner, next := nsec.Hdr.Name, nsec.NextDomain
+	afterOwner := canonicalCompare(name, owner) > 0
+
+	if canonicalCompare(owner, next) < 0 {
+		return afterOwner && canonicalCompare(name, next) < 0
+	}
+	return afterOwner && isSubdomainOf(name, next)
+}
+
+// canonicalCompare membandingkan dua nama dengan urutan kanonik DNS (RFC 4034 §6.1):
+// per label dari kanan, case-insensitive, nama yang lebih pendek lebih dulu
+func canonicalCompare(a, b string) int {
+	labelsA := dns.SplitDomainName(strings.ToLower(dns.Fqdn(a)))
+	labelsB := dns.SplitDomainName(strings.ToLower(dns.Fqdn(b)))
+
+	for i := 1; i <= len(labelsA) && i <= len(labelsB); i++ {
+		if cmp := strings.Compare(labelsA[len(labelsA)-i], labelsB[len(labelsB)-i]); cmp != 0 {
+			return cmp
+		}
+	}
+	return len(labelsA) - len(labelsB)
+}
+
+// ancestorName mengembalikan n label terakhir dari name (n = 0 berarti root)
+func ancestorName(name string, n int) string {
+	labels := dns.SplitDomainName(strings.ToLower(dns.Fqdn(name)))
+	if n <= 0 {
+		return "."
+	}
+	if n > len(labels) {
+		n = len(labels)
+	}
+	return dns.Fqdn(strings.Join(labels[len(labels)-n:], "."))
+}
+
+// wildcardName mengembalikan nama wildcard tepat di bawah zone
+func wildcardName(zone string) string {
+	if zone == "." {
+		return "*."
+	}
+	return "*." + zone
+}
+
+// parentName mengembalikan nama satu level di atas name
+func parentName(name string) string {
+	return ancestorName(name, dns.CountLabel(dns.Fqdn(name))-1)
+}
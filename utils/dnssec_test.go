@@ -0,0 +1,535 @@
+package utils
+
+import (
+	"crypto"
+	"encoding/base32"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// testZone adalah zone authoritative di testDNSSECServer. Zone signed punya satu DNSKEY
+// (KSK sekaligus ZSK) dan NSEC chain yang dibangun dari owner name records
+type testZone struct {
+	name    string
+	signed  bool
+	key     *dns.DNSKEY
+	signer  crypto.Signer
+	records []dns.RR
+}
+
+// testDNSSECServer menjawab query seperti resolver rekursif dengan CD bit: jawaban diambil
+// dari zone terdalam yang memuat nama dan ditandatangani on the fly
+type testDNSSECServer struct {
+	zones []*testZone
+	// replay menjawab query "name/type" dengan jawaban untuk nama lain (NSEC dari jawaban lain)
+	replay map[string]string
+	// strip menghapus RRset tipe tertentu dari jawaban query "name/type"
+	strip map[string]uint16
+	// rcode memaksa rcode untuk query "name/type"
+	rcode map[string]int
+}
+
+// newTestZone membuat zone dengan SOA dan NS di apex; zone signed mendapat DNSKEY baru
+func newTestZone(t *testing.T, name string, signed bool, records ...string) *testZone {
+	t.Helper()
+
+	zone := &testZone{name: dns.Fqdn(name), signed: signed}
+	records = append([]string{
+		zone.name + " 3600 IN SOA ns.test. admin.test. 1 3600 600 86400 300",
+		zone.name + " 3600 IN NS ns.test.",
+	}, records...)
+
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatalf("record %q: %v", record, err)
+		}
+		zone.records = append(zone.records, rr)
+	}
+
+	if signed {
+		zone.key = &dns.DNSKEY{
+			Hdr:       dns.RR_Header{Name: zone.name, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+			Flags:     257,
+			Protocol:  3,
+			Algorithm: dns.ECDSAP256SHA256,
+		}
+		priv, err := zone.key.Generate(256)
+		if err != nil {
+			t.Fatalf("generate key %s: %v", zone.name, err)
+		}
+		zone.signer = priv.(crypto.Signer)
+		zone.records = append(zone.records, zone.key)
+	}
+	return zone
+}
+
+// delegate menambahkan NS (dan DS jika child signed) untuk child ke zone parent
+func (z *testZone) delegate(child *testZone) {
+	z.records = append(z.records, &dns.NS{
+		Hdr: dns.RR_Header{Name: child.name, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 3600},
+		Ns:  "ns.test.",
+	})
+	if child.signed {
+		z.records = append(z.records, child.key.ToDS(dns.SHA256))
+	}
+}
+
+// sign membuat RRSIG untuk RRset dengan key zone
+func (z *testZone) sign(t *testing.T, rrset []dns.RR) []dns.RR {
+	if !z.signed || len(rrset) == 0 {
+		return rrset
+	}
+
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: rrset[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 3600},
+		Algorithm:  z.key.Algorithm,
+		Expiration: uint32(time.Now().Add(24 * time.Hour).Unix()),
+		Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+		KeyTag:     z.key.KeyTag(),
+		SignerName: z.name,
+	}
+	if err := sig.Sign(z.signer, rrset); err != nil {
+		t.Errorf("sign %s: %v", rrset[0].Header().Name, err)
+	}
+	return append(rrset, sig)
+}
+
+// rrset mengembalikan records zone dengan owner dan tipe tertentu
+func (z *testZone) rrset(name string, qtype uint16) []dns.RR {
+	var rrs []dns.RR
+	for _, rr := range z.records {
+		if strings.EqualFold(rr.Header().Name, name) && rr.Header().Rrtype == qtype {
+			rrs = append(rrs, rr)
+		}
+	}
+	return rrs
+}
+
+// nsecChain membangun NSEC untuk setiap owner name dalam urutan kanonik
+func (z *testZone) nsecChain() []*dns.NSEC {
+	types := make(map[string][]uint16)
+	var owners []string
+	for _, rr := range z.records {
+		owner := strings.ToLower(rr.Header().Name)
+		if _, ok := types[owner]; !ok {
+			owners = append(owners, owner)
+		}
+		types[owner] = append(types[owner], rr.Header().Rrtype)
+	}
+	sort.Slice(owners, func(i, j int) bool { return canonicalCompare(owners[i], owners[j]) < 0 })
+
+	var chain []*dns.NSEC
+	for i, owner := range owners {
+		bitmap := append(types[owner], dns.TypeNSEC, dns.TypeRRSIG)
+		sort.Slice(bitmap, func(a, b int) bool { return bitmap[a] < bitmap[b] })
+		chain = append(chain, &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: owner, Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+			NextDomain: owners[(i+1)%len(owners)],
+			TypeBitMap: bitmap,
+		})
+	}
+	return chain
+}
+
+// zoneFor mengembalikan zone terdalam yang authoritative untuk query; DS dijawab zone parent
+func (s *testDNSSECServer) zoneFor(name string, qtype uint16) *testZone {
+	var best *testZone
+	for _, zone := range s.zones {
+		if !isSubdomainOf(name, zone.name) || (qtype == dns.TypeDS && strings.EqualFold(name, zone.name) && zone.name != ".") {
+			continue
+		}
+		if best == nil || dns.CountLabel(zone.name) > dns.CountLabel(best.name) {
+			best = zone
+		}
+	}
+	return best
+}
+
+// answer menyusun response untuk name/qtype termasuk CNAME chain dan bukti denial
+func (s *testDNSSECServer) answer(t *testing.T, msg *dns.Msg, name string, qtype uint16) {
+	for hops := 0; hops < 8; hops++ {
+		zone := s.zoneFor(name, qtype)
+		if rrs := zone.rrset(name, qtype); len(rrs) > 0 {
+			msg.Answer = append(msg.Answer, zone.sign(t, rrs)...)
+			return
+		}
+		if cnames := zone.rrset(name, dns.TypeCNAME); len(cnames) > 0 && qtype != dns.TypeCNAME {
+			msg.Answer = append(msg.Answer, zone.sign(t, cnames)...)
+			name = cnames[0].(*dns.CNAME).Target
+			continue
+		}
+
+		msg.Ns = append(msg.Ns, zone.sign(t, zone.rrset(zone.name, dns.TypeSOA))...)
+		if !zone.signed {
+			if !zone.exists(name) {
+				msg.Rcode = dns.RcodeNameError
+			}
+			return
+		}
+
+		chain := zone.nsecChain()
+		proofs := make(map[string]*dns.NSEC)
+		if zone.exists(name) {
+			for _, nsec := range chain {
+				// Empty non-terminal dibuktikan NSEC yang mencakup nama
+				if strings.EqualFold(nsec.Hdr.Name, name) || nsecCovers(nsec, name) {
+					proofs[nsec.Hdr.Name] = nsec
+				}
+			}
+		} else {
+			msg.Rcode = dns.RcodeNameError
+			for _, target := range []string{name, "*." + zone.name} {
+				for _, nsec := range chain {
+					if nsecCovers(nsec, target) {
+						proofs[nsec.Hdr.Name] = nsec
+					}
+				}
+			}
+		}
+		for _, nsec := range proofs {
+			msg.Ns = append(msg.Ns, zone.sign(t, []dns.RR{nsec})...)
+		}
+		return
+	}
+}
+
+// exists mengecek apakah name ada di zone, termasuk sebagai empty non-terminal
+func (z *testZone) exists(name string) bool {
+	for _, rr := range z.records {
+		if isSubdomainOf(rr.Header().Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// start menjalankan server dan mengembalikan resolver dengan trust anchor root zone test
+func (s *testDNSSECServer) start(t *testing.T) *DNSResolver {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+		msg.SetEdns0(dns.DefaultMsgSize, true)
+
+		question := r.Question[0]
+		key := strings.ToLower(question.Name) + "/" + dns.TypeToString[question.Qtype]
+		name := question.Name
+		if other, ok := s.replay[key]; ok {
+			name = other
+		}
+
+		if rcode, ok := s.rcode[key]; ok {
+			msg.Rcode = rcode
+		} else {
+			s.answer(t, msg, name, question.Qtype)
+		}
+
+		if qtype, ok := s.strip[key]; ok {
+			msg.Answer = removeType(msg.Answer, qtype)
+			msg.Ns = removeType(msg.Ns, qtype)
+		}
+		w.WriteMsg(msg)
+	})}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	resolver, err := NewDNSResolver(false, NewLogger(false, true))
+	if err != nil {
+		t.Fatalf("resolver: %v", err)
+	}
+	resolver.servers = []string{conn.LocalAddr().String()}
+	resolver.trustAnchors = []*dns.DS{s.zones[0].key.ToDS(dns.SHA256)}
+	return resolver
+}
+
+// removeType menghapus records bertipe qtype beserta RRSIG-nya
+func removeType(rrs []dns.RR, qtype uint16) []dns.RR {
+	var kept []dns.RR
+	for _, rr := range rrs {
+		if sig, ok := rr.(*dns.RRSIG); ok && sig.TypeCovered == qtype {
+			continue
+		}
+		if rr.Header().Rrtype != qtype {
+			kept = append(kept, rr)
+		}
+	}
+	return kept
+}
+
+// newTestHierarchy membuat root → test → example.test (signed), other.test (signed)
+// dan insecure.test (tanpa DS)
+func newTestHierarchy(t *testing.T) *testDNSSECServer {
+	root := newTestZone(t, ".", true)
+	tld := newTestZone(t, "test.", true)
+	example := newTestZone(t, "example.test.", true,
+		"www.example.test. 300 IN A 192.0.2.10",
+		"txt.example.test. 300 IN TXT \"v=spf1 -all\"",
+		"cdn.example.test. 300 IN CNAME edge.other.test.",
+		"dangling.example.test. 300 IN CNAME gone.example.test.",
+		"host.deep.example.test. 300 IN A 192.0.2.11",
+	)
+	other := newTestZone(t, "other.test.", true, "edge.other.test. 300 IN A 192.0.2.20")
+	insecure := newTestZone(t, "insecure.test.", false, "www.insecure.test. 300 IN A 192.0.2.30")
+
+	root.delegate(tld)
+	tld.delegate(example)
+	tld.delegate(other)
+	tld.delegate(insecure)
+
+	return &testDNSSECServer{
+		zones:  []*testZone{root, tld, example, other, insecure},
+		replay: make(map[string]string),
+		strip:  make(map[string]uint16),
+		rcode:  make(map[string]int),
+	}
+}
+
+func TestValidateDNSSEC(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		tamper  func(s *testDNSSECServer)
+		status  string
+		failing string
+	}{
+		{"A signed", "www.example.test", nil, DNSSECSecure, ""},
+		{"CNAME ke zone signed lain", "cdn.example.test", nil, DNSSECSecure, ""},
+		{"NODATA terbukti NSEC", "txt.example.test", nil, DNSSECSecure, ""},
+		{"NXDOMAIN terbukti NSEC", "missing.example.test", nil, DNSSECSecure, ""},
+		{"empty non-terminal", "deep.example.test", nil, DNSSECSecure, ""},
+		{"CNAME ke NXDOMAIN terbukti", "dangling.example.test", nil, DNSSECSecure, ""},
+		{"delegasi tanpa DS terbukti", "www.insecure.test", nil, DNSSECInsecure, "insecure.test."},
+		{"query A gagal", "www.example.test", func(s *testDNSSECServer) {
+			s.rcode["www.example.test./A"] = dns.RcodeRefused
+		}, DNSSECIndeterminate, "www.example.test."},
+		{"query DS gagal", "www.example.test", func(s *testDNSSECServer) {
+			s.rcode["example.test./DS"] = dns.RcodeRefused
+		}, DNSSECIndeterminate, "example.test."},
+		{"NXDOMAIN tanpa NSEC", "missing.example.test", func(s *testDNSSECServer) {
+			s.strip["missing.example.test./A"] = dns.TypeNSEC
+		}, DNSSECBogus, "missing.example.test."},
+		{"NODATA dengan NSEC nama lain", "txt.example.test", func(s *testDNSSECServer) {
+			s.replay["txt.example.test./A"] = "example.test."
+		}, DNSSECBogus, "txt.example.test."},
+		{"A dihapus tanpa bukti", "www.example.test", func(s *testDNSSECServer) {
+			s.strip["www.example.test./A"] = dns.TypeA
+		}, DNSSECBogus, "www.example.test."},
+		{"DS dihapus tanpa NSEC", "www.example.test", func(s *testDNSSECServer) {
+			s.strip["example.test./DS"] = dns.TypeDS
+		}, DNSSECBogus, "example.test."},
+		{"DS dihapus diganti NSEC delegasi lain", "www.example.test", func(s *testDNSSECServer) {
+			s.replay["example.test./DS"] = "insecure.test."
+		}, DNSSECBogus, "example.test."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestHierarchy(t)
+			if tt.tamper != nil {
+				tt.tamper(server)
+			}
+			resolver := server.start(t)
+
+			result := resolver.ValidateDNSSEC(tt.domain)
+			if result.Status != tt.status || result.FailingLink != tt.failing {
+				t.Errorf("status = %s failing = %q (%s), want %s %q",
+					result.Status, result.FailingLink, result.Reason, tt.status, tt.failing)
+			}
+		})
+	}
+}
+
+// testNSEC membuat NSEC owner → next dengan type bitmap
+func testNSEC(owner, next string, types ...uint16) *dns.NSEC {
+	return &dns.NSEC{
+		Hdr:        dns.RR_Header{Name: dns.Fqdn(owner), Rrtype: dns.TypeNSEC, Class: dns.ClassINET},
+		NextDomain: dns.Fqdn(next),
+		TypeBitMap: types,
+	}
+}
+
+// testNSEC3 membuat NSEC3 (SHA-1, 0 iterasi, tanpa salt) dengan owner hash(name) dan
+// span hash yang digeser sebesar delta dari hash name (delta 0 berarti span kosong)
+func testNSEC3(zone, name string, delta int64, flags uint8, types ...uint16) *dns.NSEC3 {
+	hash := dns.HashName(dns.Fqdn(name), dns.SHA1, 0, "")
+	return &dns.NSEC3{
+		Hdr:        dns.RR_Header{Name: hash + "." + dns.Fqdn(zone), Rrtype: dns.TypeNSEC3, Class: dns.ClassINET},
+		Hash:       dns.SHA1,
+		Flags:      flags,
+		SaltLength: 0,
+		HashLength: 20,
+		NextDomain: shiftHash(hash, delta),
+		TypeBitMap: types,
+	}
+}
+
+// testNSEC3Covering membuat NSEC3 yang span-nya tepat mencakup hash name
+func testNSEC3Covering(zone, name string, flags uint8) *dns.NSEC3 {
+	hash := dns.HashName(dns.Fqdn(name), dns.SHA1, 0, "")
+	nsec3 := testNSEC3(zone, name, 1, flags)
+	nsec3.Hdr.Name = shiftHash(hash, -1) + "." + dns.Fqdn(zone)
+	return nsec3
+}
+
+// shiftHash menambah delta ke hash NSEC3 (base32hex 160 bit)
+func shiftHash(hash string, delta int64) string {
+	raw, err := base32.HexEncoding.DecodeString(hash)
+	if err != nil {
+		return hash
+	}
+	value := new(big.Int).Add(new(big.Int).SetBytes(raw), big.NewInt(delta))
+	out := value.FillBytes(make([]byte, len(raw)))
+	return base32.HexEncoding.EncodeToString(out)
+}
+
+func TestDenialProofNSEC(t *testing.T) {
+	proof := &denialProof{nsec: []*dns.NSEC{
+		testNSEC("example.test", "a.example.test", dns.TypeSOA, dns.TypeNS),
+		testNSEC("a.example.test", "b.x.example.test", dns.TypeA, dns.TypeTXT),
+		testNSEC("b.x.example.test", "cname.example.test", dns.TypeA),
+		testNSEC("cname.example.test", "child.example.test", dns.TypeCNAME),
+		testNSEC("child.example.test", "signed.example.test", dns.TypeNS),
+		testNSEC("signed.example.test", "zzz.example.test", dns.TypeNS, dns.TypeDS),
+		testNSEC("zzz.example.test", "example.test", dns.TypeA),
+	}}
+
+	tests := []struct {
+		name  string
+		check func() error
+		ok    bool
+	}{
+		{"NODATA owner match", func() error { return proof.proveNoData("a.example.test", dns.TypeAAAA) }, true},
+		{"NODATA tipe ada", func() error { return proof.proveNoData("a.example.test", dns.TypeA) }, false},
+		{"NODATA CNAME ada", func() error { return proof.proveNoData("cname.example.test", dns.TypeA) }, false},
+		{"NODATA empty non-terminal", func() error { return proof.proveNoData("x.example.test", dns.TypeA) }, true},
+		{"NODATA nama tidak ada", func() error { return proof.proveNoData("b.example.test", dns.TypeA) }, false},
+		{"NXDOMAIN dengan wildcard", func() error { return proof.proveNXDomain("bb.example.test") }, true},
+		{"NXDOMAIN setelah NSEC terakhir", func() error { return proof.proveNXDomain("zzzz.example.test") }, true},
+		{"NXDOMAIN nama ada", func() error { return proof.proveNXDomain("a.example.test") }, false},
+		{"DS delegasi insecure", func() error { return proof.proveNoDS("child.example.test") }, true},
+		{"DS bit ada", func() error { return proof.proveNoDS("signed.example.test") }, false},
+		{"DS NSEC apex child", func() error { return proof.proveNoDS("example.test") }, false},
+		{"DS bukan delegasi", func() error { return proof.proveNoDS("a.example.test") }, false},
+		{"DS NSEC nama lain", func() error { return proof.proveNoDS("other.example.test") }, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.check(); (err == nil) != tt.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+
+	// Tanpa NSEC yang mencakup wildcard, NXDOMAIN belum terbukti
+	partial := &denialProof{nsec: []*dns.NSEC{testNSEC("b.example.test", "d.example.test", dns.TypeA)}}
+	if err := partial.proveNXDomain("c.example.test"); err == nil {
+		t.Errorf("NXDOMAIN tanpa bukti wildcard diterima")
+	}
+}
+
+func TestDenialProofNSEC3(t *testing.T) {
+	zone := "example.test"
+
+	tests := []struct {
+		name  string
+		proof *denialProof
+		check func(p *denialProof) error
+		ok    bool
+	}{
+		{"NODATA hash match", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "www.example.test", 1, 0, dns.TypeTXT),
+		}}, func(p *denialProof) error { return p.proveNoData("www.example.test", dns.TypeA) }, true},
+		{"NODATA tipe ada", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "www.example.test", 1, 0, dns.TypeA),
+		}}, func(p *denialProof) error { return p.proveNoData("www.example.test", dns.TypeA) }, false},
+		{"NXDOMAIN closest encloser", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "example.test", 1, 0, dns.TypeSOA, dns.TypeNS),
+			testNSEC3Covering(zone, "missing.example.test", 0),
+			testNSEC3Covering(zone, "*.example.test", 0),
+		}}, func(p *denialProof) error { return p.proveNXDomain("missing.example.test") }, true},
+		{"NXDOMAIN tanpa wildcard", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "example.test", 1, 0, dns.TypeSOA, dns.TypeNS),
+			testNSEC3Covering(zone, "missing.example.test", 0),
+		}}, func(p *denialProof) error { return p.proveNXDomain("missing.example.test") }, false},
+		{"NXDOMAIN next closer tidak dicakup", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "example.test", 1, 0, dns.TypeSOA, dns.TypeNS),
+			testNSEC3Covering(zone, "*.example.test", 0),
+		}}, func(p *denialProof) error { return p.proveNXDomain("missing.example.test") }, false},
+		{"DS delegasi match", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "child.example.test", 1, 0, dns.TypeNS),
+		}}, func(p *denialProof) error { return p.proveNoDS("child.example.test") }, true},
+		{"DS bit ada", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "child.example.test", 1, 0, dns.TypeNS, dns.TypeDS),
+		}}, func(p *denialProof) error { return p.proveNoDS("child.example.test") }, false},
+		{"DS span opt-out", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "example.test", 1, 0, dns.TypeSOA, dns.TypeNS),
+			testNSEC3Covering(zone, "child.example.test", nsec3OptOut),
+		}}, func(p *denialProof) error { return p.proveNoDS("child.example.test") }, true},
+		{"DS span tanpa opt-out", &denialProof{nsec3: []*dns.NSEC3{
+			testNSEC3(zone, "example.test", 1, 0, dns.TypeSOA, dns.TypeNS),
+			testNSEC3Covering(zone, "child.example.test", 0),
+		}}, func(p *denialProof) error { return p.proveNoDS("child.example.test") }, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.check(tt.proof); (err == nil) != tt.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestVerifyDenialRequiresSignature(t *testing.T) {
+	zone := newTestZone(t, "example.test.", true)
+	forged := newTestZone(t, "example.test.", true)
+	nsec := testNSEC("child.example.test", "example.test", dns.TypeNS)
+
+	tests := []struct {
+		name    string
+		records []dns.RR
+		ok      bool
+	}{
+		{"signature valid", zone.sign(t, []dns.RR{nsec}), true},
+		{"tanpa RRSIG", []dns.RR{nsec}, false},
+		{"key lain", forged.sign(t, []dns.RR{nsec}), false},
+	}
+
+	for _, tt := range tests {
+		resp := &dns.Msg{Ns: tt.records}
+		err := verifyDenial(resp, "child.example.test.", dns.TypeDS, zone.name, []*dns.DNSKEY{zone.key})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestCanonicalCompare(t *testing.T) {
+	// Urutan contoh RFC 4034 §6.1
+	ordered := []string{
+		"example.", "a.example.", "yljkjljk.a.example.", "Z.a.example.",
+		"zABC.a.EXAMPLE.", "z.example.", "*.z.example.",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		if canonicalCompare(ordered[i], ordered[i+1]) >= 0 {
+			t.Errorf("canonicalCompare(%s, %s) >= 0", ordered[i], ordered[i+1])
+		}
+		if canonicalCompare(ordered[i+1], ordered[i]) <= 0 {
+			t.Errorf("canonicalCompare(%s, %s) <= 0", ordered[i+1], ordered[i])
+		}
+	}
+	if canonicalCompare("A.Example.", "a.example") != 0 {
+		t.Errorf("canonicalCompare tidak case-insensitive")
+	}
+}
@@ -0,0 +1,623 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// DefaultDKIMSelectors adalah selector DKIM umum yang di-probe jika tidak ada list
+var DefaultDKIMSelectors = []string{
+	"default", "dkim", "mail", "smtp", "s1", "s2", "k1", "k2", "k3",
+	"selector1", "selector2", "google", "mandrill", "mxvault", "zoho",
+	"protonmail", "protonmail2", "fm1", "fm2", "fm3",
+}
+
+// spfLookupLimit adalah batas DNS lookup SPF menurut RFC 7208 section 4.6.4
+const spfLookupLimit = 10
+
+// spfMaxDepth membatasi kedalaman include/redirect saat ekspansi
+const spfMaxDepth = 10
+
+// EmailSecurity menyimpan postur keamanan email untuk satu domain
+type EmailSecurity struct {
+	Domain    string        `json:"domain"`
+	SPF       *SPFResult    `json:"spf,omitempty"`
+	DMARC     *DMARCResult  `json:"dmarc,omitempty"`
+	DKIM      []DKIMResult  `json:"dkim"`
+	MTASTS    *MTASTSResult `json:"mta_sts,omitempty"`
+	TLSRPT    *TLSRPTResult `json:"tls_rpt,omitempty"`
+	BIMI      *BIMIResult   `json:"bimi,omitempty"`
+	Findings  []Finding     `json:"findings"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// SPFResult menyimpan SPF record beserta ekspansi include
+type SPFResult struct {
+	Record      string       `json:"record"`
+	All         string       `json:"all,omitempty"`
+	Mechanisms  []string     `json:"mechanisms"`
+	Includes    []SPFInclude `json:"includes,omitempty"`
+	LookupCount int          `json:"lookup_count"`
+	UsesPTR     bool         `json:"uses_ptr,omitempty"`
+	Errors      []string     `json:"errors,omitempty"`
+}
+
+// SPFInclude menyimpan satu include/redirect hasil ekspansi rekursif
+type SPFInclude struct {
+	Domain string `json:"domain"`
+	Record string `json:"record,omitempty"`
+	Depth  int    `json:"depth"`
+	Error  string `json:"error,omitempty"`
+}
+
+// DMARCResult menyimpan policy DMARC di _dmarc
+type DMARCResult struct {
+	Record          string   `json:"record"`
+	Policy          string   `json:"policy"`
+	SubdomainPolicy string   `json:"subdomain_policy,omitempty"`
+	Percent         int      `json:"pct"`
+	RUA             []string `json:"rua,omitempty"`
+	RUF             []string `json:"ruf,omitempty"`
+	ADKIM           string   `json:"adkim"`
+	ASPF            string   `json:"aspf"`
+}
+
+// DKIMResult menyimpan satu DKIM selector yang ditemukan
+type DKIMResult struct {
+	Selector string `json:"selector"`
+	Record   string `json:"record"`
+	KeyType  string `json:"key_type"`
+	KeySize  int    `json:"key_size,omitempty"`
+	Revoked  bool   `json:"revoked,omitempty"`
+}
+
+// MTASTSResult menyimpan record _mta-sts dan policy yang di-fetch via HTTPS
+type MTASTSResult struct {
+	Record      string   `json:"record"`
+	ID          string   `json:"id,omitempty"`
+	Mode        string   `json:"mode,omitempty"`
+	MX          []string `json:"mx,omitempty"`
+	MaxAge      int      `json:"max_age,omitempty"`
+	PolicyError string   `json:"policy_error,omitempty"`
+}
+
+// TLSRPTResult menyimpan record _smtp._tls
+type TLSRPTResult struct {
+	Record string   `json:"record"`
+	RUA    []string `json:"rua,omitempty"`
+}
+
+// BIMIResult menyimpan record default._bimi
+type BIMIResult struct {
+	Record    string `json:"record"`
+	Logo      string `json:"logo,omitempty"`
+	Authority string `json:"authority,omitempty"`
+}
+
+// AnalyzeEmailSecurity mengevaluasi SPF, DMARC, DKIM, MTA-STS, TLS-RPT dan BIMI
+func (d *DNSResolver) AnalyzeEmailSecurity(domain string, records map[string][]string, selectors []string) *EmailSecurity {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if len(selectors) == 0 {
+		selectors = DefaultDKIMSelectors
+	}
+
+	result := &EmailSecurity{
+		Domain:    domain,
+		DKIM:      make([]DKIMResult, 0),
+		Findings:  make([]Finding, 0),
+		Timestamp: time.Now(),
+	}
+
+	// MX dari ResolveAll jika tersedia
+	mxRecords := records["MX"]
+	if len(mxRecords) == 0 {
+		mxRecords, _ = d.LookupMX(domain)
+	}
+
+	result.SPF = d.analyzeSPF(domain, result)
+	result.DMARC = d.analyzeDMARC(domain, result)
+	result.DKIM = d.probeDKIM(domain, selectors, result)
+	result.MTASTS = d.analyzeMTASTS(domain, mxRecords, result)
+	result.TLSRPT = d.analyzeTLSRPT(domain, result)
+	result.BIMI = d.analyzeBIMI(domain, result)
+
+	return result
+}
+
+// addFinding menambahkan finding kategori email
+func (e *EmailSecurity) addFinding(severity, title, detail string) {
+	e.Findings = append(e.Findings, NewFinding(severity, "email", title, detail))
+}
+
+// analyzeSPF mem-parse SPF dan meng-expand include secara rekursif
+func (d *DNSResolver) analyzeSPF(domain string, result *EmailSecurity) *SPFResult {
+	records, err := d.lookupPolicyTXT(domain, "v=spf1")
+	if err != nil || len(records) == 0 {
+		result.addFinding(SeverityMedium, "SPF record tidak ditemukan", "domain dapat dipalsukan sebagai pengirim")
+		return nil
+	}
+
+	if len(records) > 1 {
+		result.addFinding(SeverityHigh, "Lebih dari satu SPF record", "RFC 7208 menganggap ini permerror")
+	}
+
+	spf := &SPFResult{
+		Record:     records[0],
+		Mechanisms: strings.Fields(records[0])[1:],
+	}
+
+	stack := map[string]bool{domain: true}
+	spf.All = d.expandSPF(spf, records[0], 0, stack)
+
+	switch spf.All {
+	case "+all":
+		result.addFinding(SeverityHigh, "SPF +all", "semua server diizinkan mengirim email atas nama domain")
+	case "?all":
+		result.addFinding(SeverityMedium, "SPF ?all (neutral)", "server tidak terdaftar tidak ditolak")
+	case "~all":
+		result.addFinding(SeverityLow, "SPF ~all (softfail)", "pertimbangkan -all setelah DMARC enforce")
+	case "":
+		result.addFinding(SeverityMedium, "SPF tanpa mekanisme all", "default hasil adalah neutral")
+	}
+
+	if spf.LookupCount > spfLookupLimit {
+		result.addFinding(SeverityHigh, "SPF melebihi batas DNS lookup",
+			fmt.Sprintf("%d lookup (maksimum %d), evaluasi menjadi permerror", spf.LookupCount, spfLookupLimit))
+	}
+
+	if spf.UsesPTR {
+		result.addFinding(SeverityLow, "SPF memakai mekanisme ptr", "ptr tidak disarankan oleh RFC 7208")
+	}
+
+	for _, spfErr := range spf.Errors {
+		result.addFinding(SeverityMedium, "SPF include bermasalah", spfErr)
+	}
+
+	return spf
+}
+
+// expandSPF menghitung DNS lookup dan meng-expand include/redirect, mengembalikan qualifier all
+func (d *DNSResolver) expandSPF(spf *SPFResult, record string, depth int, stack map[string]bool) string {
+	all := ""
+	redirect := ""
+
+	for _, term := range strings.Fields(record)[1:] {
+		lower := strings.ToLower(term)
+		qualifier := "+"
+		if strings.IndexAny(lower[:1], "+-~?") == 0 {
+			qualifier, lower = lower[:1], lower[1:]
+		}
+
+		name, value := lower, ""
+		if i := strings.IndexAny(lower, ":="); i >= 0 {
+			name, value = lower[:i], lower[i+1:]
+		}
+		if i := strings.Index(name, "/"); i >= 0 {
+			name = name[:i]
+		}
+
+		switch name {
+		case "include":
+			spf.LookupCount++
+			d.expandSPFTarget(spf, value, depth+1, stack)
+		case "a", "mx", "exists":
+			spf.LookupCount++
+		case "ptr":
+			spf.LookupCount++
+			spf.UsesPTR = true
+		case "redirect":
+			redirect = value
+		case "all":
+			all = qualifier + "all"
+		}
+	}
+
+	// redirect diabaikan jika record sudah punya all
+	if redirect != "" && all == "" {
+		spf.LookupCount++
+		all = d.expandSPFTarget(spf, redirect, depth+1, stack)
+	}
+
+	return all
+}
+
+// expandSPFTarget mengambil SPF dari domain include/redirect dan meng-expand-nya
+func (d *DNSResolver) expandSPFTarget(spf *SPFResult, target string, depth int, stack map[string]bool) string {
+	include := SPFInclude{Domain: target, Depth: depth}
+
+	switch {
+	case strings.Contains(target, "%"):
+		include.Error = "macro SPF tidak di-expand"
+	case stack[target]:
+		include.Error = "include loop"
+	case depth > spfMaxDepth:
+		include.Error = "include terlalu dalam"
+	}
+
+	if include.Error != "" {
+		spf.Includes = append(spf.Includes, include)
+		spf.Errors = append(spf.Errors, fmt.Sprintf("%s: %s", target, include.Error))
+		return ""
+	}
+
+	records, err := d.lookupPolicyTXT(target, "v=spf1")
+	if err != nil || len(records) == 0 {
+		include.Error = "SPF record tidak ditemukan"
+		spf.Includes = append(spf.Includes, include)
+		spf.Errors = append(spf.Errors, fmt.Sprintf("%s: %s", target, include.Error))
+		return ""
+	}
+
+	include.Record = records[0]
+	spf.Includes = append(spf.Includes, include)
+
+	stack[target] = true
+	defer delete(stack, target)
+
+	return d.expandSPF(spf, records[0], depth, stack)
+}
+
+// analyzeDMARC mem-parse policy DMARC di _dmarc
+func (d *DNSResolver) analyzeDMARC(domain string, result *EmailSecurity) *DMARCResult {
+	records, err := d.lookupPolicyTXT("_dmarc."+domain, "v=DMARC1")
+	if err != nil || len(records) == 0 {
+		result.addFinding(SeverityHigh, "DMARC record tidak ditemukan", "tidak ada policy untuk email yang gagal SPF/DKIM")
+		return nil
+	}
+
+	if len(records) > 1 {
+		result.addFinding(SeverityHigh, "Lebih dari satu DMARC record", "receiver akan mengabaikan DMARC")
+	}
+
+	tags := parseTagList(records[0])
+	dmarc := &DMARCResult{
+		Record:          records[0],
+		Policy:          strings.ToLower(tags["p"]),
+		SubdomainPolicy: strings.ToLower(tags["sp"]),
+		Percent:         100,
+		RUA:             splitURIList(tags["rua"]),
+		RUF:             splitURIList(tags["ruf"]),
+		ADKIM:           "r",
+		ASPF:            "r",
+	}
+
+	if pct, err := strconv.Atoi(tags["pct"]); err == nil {
+		dmarc.Percent = pct
+	}
+	if tags["adkim"] != "" {
+		dmarc.ADKIM = strings.ToLower(tags["adkim"])
+	}
+	if tags["aspf"] != "" {
+		dmarc.ASPF = strings.ToLower(tags["aspf"])
+	}
+
+	switch dmarc.Policy {
+	case "reject", "quarantine":
+	case "none":
+		result.addFinding(SeverityMedium, "DMARC p=none", "hanya monitoring, email palsu tetap terkirim")
+	default:
+		result.addFinding(SeverityHigh, "DMARC policy tidak valid", fmt.Sprintf("p=%q", dmarc.Policy))
+	}
+
+	if dmarc.SubdomainPolicy == "none" && dmarc.Policy != "none" {
+		result.addFinding(SeverityLow, "DMARC sp=none", "subdomain tidak dilindungi policy")
+	}
+	if dmarc.Percent < 100 {
+		result.addFinding(SeverityLow, "DMARC pct < 100", fmt.Sprintf("policy hanya diterapkan ke %d%% email", dmarc.Percent))
+	}
+	if len(dmarc.RUA) == 0 {
+		result.addFinding(SeverityLow, "DMARC tanpa rua", "tidak ada laporan aggregate")
+	}
+
+	return dmarc
+}
+
+// probeDKIM mencoba setiap selector di <selector>._domainkey secara concurrent
+func (d *DNSResolver) probeDKIM(domain string, selectors []string, result *EmailSecurity) []DKIMResult {
+	probes := make([]*DKIMResult, len(selectors))
+	var wg sync.WaitGroup
+
+	for i, selector := range selectors {
+		wg.Add(1)
+		go func(idx int, sel string) {
+			defer wg.Done()
+
+			records, err := d.lookupPolicyTXT(sel+"._domainkey."+domain, "")
+			if err != nil {
+				return
+			}
+
+			for _, record := range records {
+				tags := parseTagList(record)
+				key, hasKey := tags["p"]
+				if !hasKey || (tags["v"] != "" && !strings.EqualFold(tags["v"], "DKIM1")) {
+					continue
+				}
+
+				dkim := &DKIMResult{
+					Selector: sel,
+					Record:   record,
+					KeyType:  strings.ToLower(tags["k"]),
+					Revoked:  key == "",
+				}
+				if dkim.KeyType == "" {
+					dkim.KeyType = "rsa"
+				}
+				if !dkim.Revoked {
+					dkim.KeySize = dkimKeySize(dkim.KeyType, key)
+				}
+
+				probes[idx] = dkim
+				return
+			}
+		}(i, selector)
+	}
+
+	wg.Wait()
+
+	found := make([]DKIMResult, 0)
+	for _, dkim := range probes {
+		if dkim == nil {
+			continue
+		}
+		found = append(found, *dkim)
+
+		switch {
+		case dkim.Revoked:
+			result.addFinding(SeverityInfo, "DKIM selector dicabut", dkim.Selector)
+		case dkim.KeyType == "rsa" && dkim.KeySize > 0 && dkim.KeySize < 1024:
+			result.addFinding(SeverityHigh, "DKIM key RSA lemah", fmt.Sprintf("%s: %d bit", dkim.Selector, dkim.KeySize))
+		case dkim.KeyType == "rsa" && dkim.KeySize > 0 && dkim.KeySize < 2048:
+			result.addFinding(SeverityMedium, "DKIM key RSA < 2048 bit", fmt.Sprintf("%s: %d bit", dkim.Selector, dkim.KeySize))
+		}
+	}
+
+	if len(found) == 0 {
+		result.addFinding(SeverityLow, "DKIM selector tidak ditemukan",
+			fmt.Sprintf("%d selector dicoba, selector sebenarnya mungkin tidak ada di list", len(selectors)))
+	}
+
+	return found
+}
+
+// analyzeMTASTS membaca _mta-sts dan mengambil policy dari mta-sts.<domain>
+func (d *DNSResolver) analyzeMTASTS(domain string, mxRecords []string, result *EmailSecurity) *MTASTSResult {
+	records, err := d.lookupPolicyTXT("_mta-sts."+domain, "v=STSv1")
+	if err != nil || len(records) == 0 {
+		result.addFinding(SeverityLow, "MTA-STS tidak dikonfigurasi", "SMTP TLS antar server dapat di-downgrade")
+		return nil
+	}
+
+	sts := &MTASTSResult{
+		Record: records[0],
+		ID:     parseTagList(records[0])["id"],
+	}
+
+	if err := d.fetchMTASTSPolicy(domain, sts); err != nil {
+		sts.PolicyError = err.Error()
+		result.addFinding(SeverityHigh, "MTA-STS policy tidak dapat diambil", err.Error())
+		return sts
+	}
+
+	switch sts.Mode {
+	case "enforce":
+	case "testing":
+		result.addFinding(SeverityLow, "MTA-STS mode testing", "kegagalan TLS hanya dilaporkan")
+	case "none":
+		result.addFinding(SeverityMedium, "MTA-STS mode none", "policy dinonaktifkan")
+	default:
+		result.addFinding(SeverityHigh, "MTA-STS mode tidak valid", fmt.Sprintf("mode=%q", sts.Mode))
+	}
+
+	for _, mx := range mxRecords {
+		host := mx
+		if fields := strings.Fields(mx); len(fields) == 2 {
+			host = fields[1]
+		}
+		if !mtaSTSMatches(host, sts.MX) {
+			result.addFinding(SeverityMedium, "MX tidak tercakup MTA-STS policy", host)
+		}
+	}
+
+	return sts
+}
+
+// fetchMTASTSPolicy mengambil https://mta-sts.<domain>/.well-known/mta-sts.txt
+func (d *DNSResolver) fetchMTASTSPolicy(domain string, sts *MTASTSResult) error {
+	url := fmt.Sprintf("https://mta-sts.%s/.well-known/mta-sts.txt", domain)
+	d.logger.LogRequest("GET", url)
+
+	resp, err := d.httpClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d dari %s", resp.StatusCode, url)
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 64*1024))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "mode":
+			sts.Mode = strings.ToLower(value)
+		case "mx":
+			sts.MX = append(sts.MX, strings.ToLower(value))
+		case "max_age":
+			sts.MaxAge, _ = strconv.Atoi(value)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// analyzeTLSRPT membaca record _smtp._tls
+func (d *DNSResolver) analyzeTLSRPT(domain string, result *EmailSecurity) *TLSRPTResult {
+	records, err := d.lookupPolicyTXT("_smtp._tls."+domain, "v=TLSRPTv1")
+	if err != nil || len(records) == 0 {
+		severity := SeverityInfo
+		if result.MTASTS != nil {
+			severity = SeverityLow
+		}
+		result.addFinding(severity, "TLS-RPT tidak dikonfigurasi", "tidak ada laporan kegagalan SMTP TLS")
+		return nil
+	}
+
+	return &TLSRPTResult{
+		Record: records[0],
+		RUA:    splitURIList(parseTagList(records[0])["rua"]),
+	}
+}
+
+// analyzeBIMI membaca record default._bimi
+func (d *DNSResolver) analyzeBIMI(domain string, result *EmailSecurity) *BIMIResult {
+	records, err := d.lookupPolicyTXT("default._bimi."+domain, "v=BIMI1")
+	if err != nil || len(records) == 0 {
+		result.addFinding(SeverityInfo, "BIMI tidak dikonfigurasi", "")
+		return nil
+	}
+
+	tags := parseTagList(records[0])
+	bimi := &BIMIResult{
+		Record:    records[0],
+		Logo:      tags["l"],
+		Authority: tags["a"],
+	}
+
+	if result.DMARC == nil || result.DMARC.Policy == "none" {
+		result.addFinding(SeverityMedium, "BIMI tanpa DMARC enforcement", "BIMI membutuhkan p=quarantine atau p=reject")
+	}
+
+	return bimi
+}
+
+// lookupPolicyTXT mengambil TXT record utuh (segment digabung tanpa spasi) dengan prefix versi
+func (d *DNSResolver) lookupPolicyTXT(name, prefix string) ([]string, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), dns.TypeTXT)
+	msg.RecursionDesired = true
+	msg.SetEdns0(d.ednsSize, false)
+
+	resp, err := d.exchange(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []string
+	for _, rr := range resp.Answer {
+		txt, ok := rr.(*dns.TXT)
+		if !ok {
+			continue
+		}
+
+		value := strings.Join(txt.Txt, "")
+		if prefix == "" || hasVersionTag(value, prefix) {
+			records = append(records, value)
+		}
+	}
+
+	return records, nil
+}
+
+// hasVersionTag mengecek apakah record diawali tag versi (mis. v=spf1)
+func hasVersionTag(record, tag string) bool {
+	lower := strings.ToLower(strings.TrimSpace(record))
+	tag = strings.ToLower(tag)
+
+	if !strings.HasPrefix(lower, tag) {
+		return false
+	}
+
+	rest := lower[len(tag):]
+	return rest == "" || rest[0] == ' ' || rest[0] == ';'
+}
+
+// parseTagList mem-parse format tag=value; (DMARC, DKIM, MTA-STS, BIMI)
+func parseTagList(record string) map[string]string {
+	tags := make(map[string]string)
+
+	for _, part := range strings.Split(record, ";") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.Join(strings.Fields(value), "")
+	}
+
+	return tags
+}
+
+// splitURIList memecah daftar URI yang dipisah koma
+func splitURIList(value string) []string {
+	var uris []string
+	for _, uri := range strings.Split(value, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// dkimKeySize menghitung ukuran public key DKIM dalam bit
+func dkimKeySize(keyType, encoded string) int {
+	if keyType == "ed25519" {
+		return 256
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0
+	}
+
+	if pub, err := x509.ParsePKIXPublicKey(raw); err == nil {
+		if rsaKey, ok := pub.(*rsa.PublicKey); ok {
+			return rsaKey.N.BitLen()
+		}
+		return 0
+	}
+
+	if rsaKey, err := x509.ParsePKCS1PublicKey(raw); err == nil {
+		return rsaKey.N.BitLen()
+	}
+
+	return 0
+}
+
+// mtaSTSMatches mengecek apakah host MX cocok dengan pola mx di policy
+func mtaSTSMatches(host string, patterns []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, ".")
+		if strings.HasPrefix(pattern, "*.") {
+			suffix := pattern[1:]
+			if strings.HasSuffix(host, suffix) && !strings.Contains(strings.TrimSuffix(host, suffix), ".") {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// newTXTResolver menjalankan DNS server lokal yang menjawab TXT dari map nama → records
+func newTXTResolver(t *testing.T, records map[string][]string) *DNSResolver {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	server := &dns.Server{PacketConn: conn, Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		msg := new(dns.Msg)
+		msg.SetReply(r)
+
+		question := r.Question[0]
+		name := strings.ToLower(strings.TrimSuffix(question.Name, "."))
+		values, ok := records[name]
+		if !ok {
+			msg.Rcode = dns.RcodeNameError
+		} else if question.Qtype == dns.TypeTXT {
+			for _, value := range values {
+				msg.Answer = append(msg.Answer, &dns.TXT{
+					Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 60},
+					Txt: []string{value},
+				})
+			}
+		}
+		w.WriteMsg(msg)
+	})}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	resolver, err := NewDNSResolver(false, NewLogger(false, true))
+	if err != nil {
+		t.Fatalf("resolver: %v", err)
+	}
+	resolver.servers = []string{conn.LocalAddr().String()}
+	return resolver
+}
+
+func TestParseTagList(t *testing.T) {
+	tests := []struct {
+		record string
+		want   map[string]string
+	}{
+		{"v=DMARC1; p=reject", map[string]string{"v": "DMARC1", "p": "reject"}},
+		{"v=DMARC1;p=none;pct=50;", map[string]string{"v": "DMARC1", "p": "none", "pct": "50"}},
+		{"V=DMARC1; P = quarantine ", map[string]string{"v": "DMARC1", "p": "quarantine"}},
+		{"v=DKIM1; k=rsa; p=MIIB IjAN Bgkq", map[string]string{"v": "DKIM1", "k": "rsa", "p": "MIIBIjANBgkq"}},
+		{"v=DMARC1; rua=mailto:a@example.com,mailto:b@example.com", map[string]string{"v": "DMARC1", "rua": "mailto:a@example.com,mailto:b@example.com"}},
+		{"v=DMARC1; invalid; p=reject", map[string]string{"v": "DMARC1", "p": "reject"}},
+		{"", map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		if got := parseTagList(tt.record); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTagList(%q) = %v, want %v", tt.record, got, tt.want)
+		}
+	}
+}
+
+func TestHasVersionTag(t *testing.T) {
+	tests := []struct {
+		record string
+		tag    string
+		want   bool
+	}{
+		{"v=spf1 -all", "v=spf1", true},
+		{"V=SPF1 include:example.com ~all", "v=spf1", true},
+		{"v=spf1", "v=spf1", true},
+		{"v=spf10 -all", "v=spf1", false},
+		{"v=DMARC1; p=none", "v=DMARC1", true},
+		{"v=DMARC1p=none", "v=DMARC1", false},
+		{"google-site-verification=abc", "v=spf1", false},
+	}
+
+	for _, tt := range tests {
+		if got := hasVersionTag(tt.record, tt.tag); got != tt.want {
+			t.Errorf("hasVersionTag(%q, %q) = %v, want %v", tt.record, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestSplitURIList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"mailto:a@example.com", []string{"mailto:a@example.com"}},
+		{"mailto:a@example.com, mailto:b@example.com", []string{"mailto:a@example.com", "mailto:b@example.com"}},
+		{"mailto:a@example.com,,", []string{"mailto:a@example.com"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := splitURIList(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitURIList(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeSPFLookupCount(t *testing.T) {
+	resolver := newTXTResolver(t, map[string][]string{
+		"plain.test":    {"v=spf1 ip4:192.0.2.0/24 -all"},
+		"mechs.test":    {"v=spf1 a mx/24 exists:%{i}.bl.test ptr ~all"},
+		"include.test":  {"v=spf1 include:one.test include:two.test -all"},
+		"one.test":      {"v=spf1 a mx -all"},
+		"two.test":      {"v=spf1 include:three.test ?all"},
+		"three.test":    {"v=spf1 ip6:2001:db8::/32 -all"},
+		"redirect.test": {"v=spf1 redirect=one.test"},
+		"loop.test":     {"v=spf1 include:loop2.test -all"},
+		"loop2.test":    {"v=spf1 include:loop.test -all"},
+		"missing.test":  {"v=spf1 include:nowhere.test -all"},
+		"over.test":     {"v=spf1 a mx include:one.test include:one.test include:one.test include:one.test -all"},
+	})
+
+	tests := []struct {
+		domain  string
+		lookups int
+		all     string
+		usesPTR bool
+		errors  int
+	}{
+		{"plain.test", 0, "-all", false, 0},
+		{"mechs.test", 4, "~all", true, 0},
+		// include one (1) + a, mx di one (2) + include two (1) + include three (1)
+		{"include.test", 5, "-all", false, 0},
+		// redirect (1) + a, mx di one (2); all diambil dari target redirect
+		{"redirect.test", 3, "-all", false, 0},
+		{"loop.test", 2, "-all", false, 1},
+		{"missing.test", 1, "-all", false, 1},
+		// a, mx (2) + 4 × (include + a + mx)
+		{"over.test", 14, "-all", false, 0},
+	}
+
+	for _, tt := range tests {
+		result := &EmailSecurity{Domain: tt.domain}
+		spf := resolver.analyzeSPF(tt.domain, result)
+		if spf == nil {
+			t.Fatalf("analyzeSPF(%s) = nil", tt.domain)
+		}
+
+		if spf.LookupCount != tt.lookups {
+			t.Errorf("%s: LookupCount = %d, want %d", tt.domain, spf.LookupCount, tt.lookups)
+		}
+		if spf.All != tt.all {
+			t.Errorf("%s: All = %q, want %q", tt.domain, spf.All, tt.all)
+		}
+		if spf.UsesPTR != tt.usesPTR {
+			t.Errorf("%s: UsesPTR = %v, want %v", tt.domain, spf.UsesPTR, tt.usesPTR)
+		}
+		if len(spf.Errors) != tt.errors {
+			t.Errorf("%s: Errors = %v, want %d error", tt.domain, spf.Errors, tt.errors)
+		}
+
+		overLimit := hasFinding(result.Findings, "SPF melebihi batas DNS lookup")
+		if overLimit != (tt.lookups > spfLookupLimit) {
+			t.Errorf("%s: finding batas lookup = %v dengan %d lookup", tt.domain, overLimit, tt.lookups)
+		}
+	}
+}
+
+func TestAnalyzeDMARC(t *testing.T) {
+	resolver := newTXTResolver(t, map[string][]string{
+		"_dmarc.reject.test":  {"v=DMARC1; p=reject; sp=none; pct=50; rua=mailto:a@reject.test,mailto:b@reject.test; adkim=S; aspf=s"},
+		"_dmarc.minimal.test": {"v=DMARC1; p=None"},
+		"_dmarc.invalid.test": {"v=DMARC1; p=block; rua=mailto:a@invalid.test"},
+	})
+
+	tests := []struct {
+		domain   string
+		policy   string
+		sp       string
+		pct      int
+		rua      []string
+		adkim    string
+		aspf     string
+		findings []string
+	}{
+		{"reject.test", "reject", "none", 50, []string{"mailto:a@reject.test", "mailto:b@reject.test"}, "s", "s",
+			[]string{"DMARC sp=none", "DMARC pct < 100"}},
+		{"minimal.test", "none", "", 100, nil, "r", "r",
+			[]string{"DMARC p=none", "DMARC tanpa rua"}},
+		{"invalid.test", "block", "", 100, []string{"mailto:a@invalid.test"}, "r", "r",
+			[]string{"DMARC policy tidak valid"}},
+	}
+
+	for _, tt := range tests {
+		result := &EmailSecurity{Domain: tt.domain}
+		dmarc := resolver.analyzeDMARC(tt.domain, result)
+		if dmarc == nil {
+			t.Fatalf("analyzeDMARC(%s) = nil", tt.domain)
+		}
+
+		if dmarc.Policy != tt.policy || dmarc.SubdomainPolicy != tt.sp || dmarc.Percent != tt.pct {
+			t.Errorf("%s: p=%q sp=%q pct=%d, want p=%q sp=%q pct=%d", tt.domain,
+				dmarc.Policy, dmarc.SubdomainPolicy, dmarc.Percent, tt.policy, tt.sp, tt.pct)
+		}
+		if !reflect.DeepEqual(dmarc.RUA, tt.rua) {
+			t.Errorf("%s: rua = %v, want %v", tt.domain, dmarc.RUA, tt.rua)
+		}
+		if dmarc.ADKIM != tt.adkim || dmarc.ASPF != tt.aspf {
+			t.Errorf("%s: adkim=%q aspf=%q, want %q %q", tt.domain, dmarc.ADKIM, dmarc.ASPF, tt.adkim, tt.aspf)
+		}
+
+		var titles []string
+		for _, finding := range result.Findings {
+			titles = append(titles, finding.Title)
+		}
+		if !reflect.DeepEqual(titles, tt.findings) {
+			t.Errorf("%s: findings = %v, want %v", tt.domain, titles, tt.findings)
+		}
+	}
+}
+
+// hasFinding mengecek apakah findings berisi judul tertentu
+func hasFinding(findings []Finding, title string) bool {
+	for _, finding := range findings {
+		if finding.Title == title {
+			return true
+		}
+	}
+	return false
+}
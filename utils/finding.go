@@ -0,0 +1,48 @@
+package utils
+
+// Severity level untuk findings
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// severityRank mengurutkan severity dari terendah ke tertinggi
+var severityRank = map[string]int{
+	SeverityInfo:     1,
+	SeverityLow:      2,
+	SeverityMedium:   3,
+	SeverityHigh:     4,
+	SeverityCritical: 5,
+}
+
+// Finding menyimpan satu temuan keamanan beserta severity
+type Finding struct {
+	Severity string `json:"severity"`
+	Category string `json:"category"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// NewFinding membuat Finding baru
+func NewFinding(severity, category, title, detail string) Finding {
+	return Finding{
+		Severity: severity,
+		Category: category,
+		Title:    title,
+		Detail:   detail,
+	}
+}
+
+// HighestSeverity mengembalikan severity tertinggi dari findings ("" jika kosong)
+func HighestSeverity(findings []Finding) string {
+	highest := ""
+	for _, finding := range findings {
+		if severityRank[finding.Severity] > severityRank[highest] {
+			highest = finding.Severity
+		}
+	}
+	return highest
+}
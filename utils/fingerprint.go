@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"math/big"
 	"net"
@@ -12,27 +13,77 @@ import (
 
 // FingerprintSpoofer mengelola TLS fingerprint spoofing
 type FingerprintSpoofer struct {
-	logger *Logger
+	logger      *Logger
+	roots       *x509.CertPool
+	rootsSource string
+	randomize    bool
+	legacyProbe  bool
+	sessionProbe bool
 }
 
 // TLSFingerprint menyimpan informasi TLS fingerprint
 type TLSFingerprint struct {
 	JA3         string            `json:"ja3,omitempty"`
+	JA3String   string            `json:"ja3_string,omitempty"`
+	JA3S        string            `json:"ja3s,omitempty"`
+	JA3SString  string            `json:"ja3s_string,omitempty"`
 	TLSVersion  string            `json:"tls_version"`
 	CipherSuite string            `json:"cipher_suite"`
 	ServerName  string            `json:"server_name"`
 	Certificate *CertificateInfo  `json:"certificate,omitempty"`
 	Extensions  map[string]string `json:"extensions,omitempty"`
+
+	Chain      []*CertificateInfo `json:"chain,omitempty"`
+	Validation *ChainValidation   `json:"validation,omitempty"`
+	SNI        *SNIComparison     `json:"sni,omitempty"`
+
+	ALPN             string      `json:"alpn,omitempty"`
+	OCSPStapled      bool        `json:"ocsp_stapled"`
+	OCSP             *OCSPStaple `json:"ocsp,omitempty"`
+	KeyExchange      string      `json:"key_exchange,omitempty"`
+	KeyExchangeGroup string      `json:"key_exchange_group,omitempty"`
+	SessionTicket    bool        `json:"session_ticket"`
+	Resumed          bool        `json:"resumed"`
+	// EarlyDataOffered hanya berarti server mengiklankan 0-RTT (max_early_data_size > 0 di
+	// NewSessionTicket); early data tidak pernah dikirim sehingga penerimaannya tidak diuji.
+	// nil jika tidak ditentukan: tanpa SetSessionProbe, TLS 1.2, atau suite selain AES-GCM
+	// (termasuk ChaCha20) karena ticket tidak bisa didekripsi
+	EarlyDataOffered *bool       `json:"early_data_offered,omitempty"`
+	MaxEarlyData     uint32      `json:"max_early_data,omitempty"`
+	HSTS             *HSTSPolicy `json:"hsts,omitempty"`
+
+	// Versi deprecated (SSL 3.0, TLS 1.0, TLS 1.1) yang diterima server, diuji dengan
+	// ClientHello terpisah karena handshake utama selalu menegosiasikan versi tertinggi
+	LegacyVersions []string `json:"legacy_versions,omitempty"`
 }
 
 // CertificateInfo menyimpan informasi sertifikat
 type CertificateInfo struct {
-	Subject     string    `json:"subject"`
-	Issuer      string    `json:"issuer"`
-	NotBefore   time.Time `json:"not_before"`
-	NotAfter    time.Time `json:"not_after"`
-	SerialNumber string   `json:"serial_number"`
-	Fingerprint string    `json:"fingerprint"`
+	Subject         string    `json:"subject"`
+	Issuer          string    `json:"issuer"`
+	NotBefore       time.Time `json:"not_before"`
+	NotAfter        time.Time `json:"not_after"`
+	SerialNumber    string    `json:"serial_number"`
+	Fingerprint     string    `json:"fingerprint"`
+	FingerprintSHA1 string    `json:"fingerprint_sha1"`
+	DaysUntilExpiry int       `json:"days_until_expiry"`
+
+	DNSNames       []string `json:"dns_names,omitempty"`
+	IPAddresses    []string `json:"ip_addresses,omitempty"`
+	EmailAddresses []string `json:"email_addresses,omitempty"`
+	URIs           []string `json:"uris,omitempty"`
+
+	KeyAlgorithm       string `json:"key_algorithm"`
+	KeySize            int    `json:"key_size,omitempty"`
+	SignatureAlgorithm string `json:"signature_algorithm"`
+	IsCA               bool   `json:"is_ca"`
+	MaxPathLen         *int   `json:"max_path_len,omitempty"`
+	SelfSigned         bool   `json:"self_signed,omitempty"`
+
+	IssuingCertificateURL []string `json:"aia_issuers,omitempty"`
+	OCSPServers           []string `json:"ocsp_servers,omitempty"`
+	CRLDistributionPoints []string `json:"crl_distribution_points,omitempty"`
+	SCTs                  int      `json:"sct_count"`
 }
 
 // NewFingerprintSpoofer membuat instance FingerprintSpoofer baru
@@ -42,37 +93,154 @@ func NewFingerprintSpoofer(logger *Logger) *FingerprintSpoofer {
 	}
 }
 
-// AnalyzeTLS menganalisis TLS connection dan fingerprint
-func (f *FingerprintSpoofer) AnalyzeTLS(target string) map[string]interface{} {
-	result := make(map[string]interface{})
+// SetRandomFingerprint mengaktifkan ClientHello random untuk mode stealth.
+// Jika handshake random gagal, handshake diulang dengan konfigurasi permissive
+// agar port TLS tetap terdeteksi
+func (f *FingerprintSpoofer) SetRandomFingerprint(enabled bool) {
+	f.randomize = enabled
+}
+
+// SetLegacyProbe mengaktifkan probe SSL 3.0/TLS 1.0/TLS 1.1 (tiga ClientHello mentah
+// tambahan per port) di AnalyzeTLSAddress
+func (f *FingerprintSpoofer) SetLegacyProbe(enabled bool) {
+	f.legacyProbe = enabled
+}
 
+// SetSessionProbe mengaktifkan uji sesi di setiap handshake: tunggu NewSessionTicket
+// TLS 1.3 dan 0-RTT, handshake kedua untuk resumption, dan request HTTP untuk HSTS
+func (f *FingerprintSpoofer) SetSessionProbe(enabled bool) {
+	f.sessionProbe = enabled
+}
+
+// AnalyzeTLS menganalisis TLS connection dan fingerprint
+func (f *FingerprintSpoofer) AnalyzeTLS(target string) (*TLSFingerprint, error) {
 	// Parse target untuk mendapatkan host dan port
 	host, port := f.parseTarget(target)
 	if port == "" {
 		port = "443" // Default HTTPS port
 	}
 
-	address := net.JoinHostPort(host, port)
+	fingerprint, err := f.AnalyzeTLSAddress(net.JoinHostPort(host, port), host, 10*time.Second)
+	if err != nil {
+		f.logger.Debug(fmt.Sprintf("TLS connection failed for %s: %v", target, err))
+		return nil, err
+	}
+
+	return fingerprint, nil
+}
 
-	// Custom TLS config untuk fingerprinting
-	tlsConfig := f.createRandomTLSConfig(host)
+// AnalyzeTLSAddress melakukan TLS handshake ke address (IP:port) dengan SNI dari hostname asli,
+// lalu menguji session resumption dan HSTS untuk port HTTPS jika SetSessionProbe aktif serta
+// versi deprecated jika SetLegacyProbe aktif
+func (f *FingerprintSpoofer) AnalyzeTLSAddress(address, host string, timeout time.Duration) (*TLSFingerprint, error) {
+	fingerprint, tlsConfig, err := f.handshakeAddress(address, host, timeout, f.randomize)
+	if err != nil && f.randomize {
+		// Fingerprint random bisa tidak cocok dengan versi/cipher server, jangan anggap bukan TLS
+		f.logger.Debug(fmt.Sprintf("Handshake random gagal untuk %s, ulang dengan konfigurasi permissive: %v", address, err))
+		fingerprint, tlsConfig, err = f.handshakeAddress(address, host, timeout, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if f.sessionProbe && fingerprint.SessionTicket {
+		fingerprint.Resumed = f.testResumption(address, tlsConfig, timeout)
+	}
 
-	// Connect dengan timeout
-	dialer := &net.Dialer{
-		Timeout: 10 * time.Second,
+	if f.legacyProbe {
+		fingerprint.LegacyVersions = probeLegacyVersions(address, tlsConfig.ServerName, timeout)
 	}
 
-	conn, err := tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	port := portOf(address)
+	if f.sessionProbe && (fingerprint.ALPN == "h2" || fingerprint.ALPN == "http/1.1" || port == "443" || port == "8443") {
+		fingerprint.HSTS = checkHSTS(address, host, timeout)
+	}
+
+	return fingerprint, nil
+}
+
+// handshakeAddress melakukan handshake dengan ALPN HTTP, lalu tanpa ALPN jika server menolaknya
+func (f *FingerprintSpoofer) handshakeAddress(address, host string, timeout time.Duration, random bool) (*TLSFingerprint, *tls.Config, error) {
+	fingerprint, tlsConfig, err := f.dialAndHandshake(address, f.newTLSConfig(host, random, httpALPN), host, timeout)
+	if err != nil && strings.Contains(err.Error(), "no application protocol") {
+		// Server non-HTTP yang menolak ALPN yang tidak dikenalnya
+		fingerprint, tlsConfig, err = f.dialAndHandshake(address, f.newTLSConfig(host, random, nil), host, timeout)
+	}
+	return fingerprint, tlsConfig, err
+}
+
+// dialAndHandshake membuka koneksi TCP ke address lalu menjalankan handshakeConn
+func (f *FingerprintSpoofer) dialAndHandshake(address string, tlsConfig *tls.Config, host string, timeout time.Duration) (*TLSFingerprint, *tls.Config, error) {
+	rawConn, err := net.DialTimeout("tcp", address, timeout)
 	if err != nil {
-		f.logger.Debug(fmt.Sprintf("TLS connection failed for %s: %v", target, err))
-		result["error"] = err.Error()
-		return result
+		return nil, nil, err
+	}
+	defer rawConn.Close()
+
+	return f.handshakeConn(rawConn, tlsConfig, host, timeout)
+}
+
+// LeafCertificateAddress hanya melakukan satu handshake permissive ke address lalu
+// mengembalikan sertifikat leaf, tanpa JA3, analisis sesi, HSTS, probe versi maupun
+// validasi chain. Dipakai monitoring expiry yang hanya butuh leaf
+func (f *FingerprintSpoofer) LeafCertificateAddress(address, host string, timeout time.Duration) (*TLSFingerprint, error) {
+	rawConn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer rawConn.Close()
+
+	conn := tls.Client(rawConn, f.createPermissiveTLSConfig(host))
+	conn.SetDeadline(time.Now().Add(timeout))
+	if err := conn.Handshake(); err != nil {
+		return nil, err
+	}
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("server tidak mengirim sertifikat")
+	}
+
+	return &TLSFingerprint{
+		TLSVersion:  f.getTLSVersionString(state.Version),
+		CipherSuite: f.getCipherSuiteString(state.CipherSuite),
+		ServerName:  state.ServerName,
+		Certificate: describeCertificate(state.PeerCertificates[0]),
+	}, nil
+}
+
+// newTLSConfig memilih konfigurasi random (stealth) atau permissive lalu mengisi ALPN
+func (f *FingerprintSpoofer) newTLSConfig(host string, random bool, alpn []string) *tls.Config {
+	tlsConfig := f.createPermissiveTLSConfig(host)
+	if random {
+		tlsConfig = f.createRandomTLSConfig(host)
+	}
+	tlsConfig.NextProtos = alpn
+	return tlsConfig
+}
+
+// handshakeConn menjalankan TLS handshake di atas koneksi yang sudah terbuka
+// (langsung atau setelah STARTTLS) lalu menganalisis chain, JA3/JA3S dan detail sesi.
+// tls.Config dikembalikan agar session cache-nya bisa dipakai untuk uji resumption
+func (f *FingerprintSpoofer) handshakeConn(rawConn net.Conn, tlsConfig *tls.Config, host string, timeout time.Duration) (*TLSFingerprint, *tls.Config, error) {
+	// Session cache dan key log untuk mendeteksi session ticket dan 0-RTT
+	cache := newTicketCache()
+	keyLog := &keyLogBuffer{}
+	tlsConfig.ClientSessionCache = cache
+	tlsConfig.KeyLogWriter = keyLog
+
+	// Rekam handshake untuk JA3/JA3S dari ClientHello dan ServerHello asli
+	recorder := newRecordingConn(rawConn)
+	conn := tls.Client(recorder, tlsConfig)
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if err := conn.Handshake(); err != nil {
+		return nil, nil, err
 	}
-	defer conn.Close()
 
 	// Analyze connection state
 	state := conn.ConnectionState()
-	
+
 	fingerprint := &TLSFingerprint{
 		TLSVersion:  f.getTLSVersionString(state.Version),
 		CipherSuite: f.getCipherSuiteString(state.CipherSuite),
@@ -80,26 +248,29 @@ func (f *FingerprintSpoofer) AnalyzeTLS(target string) map[string]interface{} {
 		Extensions:  make(map[string]string),
 	}
 
-	// Analyze certificates
+	// Analyze certificate chain
 	if len(state.PeerCertificates) > 0 {
-		cert := state.PeerCertificates[0]
-		fingerprint.Certificate = &CertificateInfo{
-			Subject:      cert.Subject.String(),
-			Issuer:       cert.Issuer.String(),
-			NotBefore:    cert.NotBefore,
-			NotAfter:     cert.NotAfter,
-			SerialNumber: cert.SerialNumber.String(),
+		for _, cert := range state.PeerCertificates {
+			fingerprint.Chain = append(fingerprint.Chain, describeCertificate(cert))
 		}
+
+		// SCT yang dikirim lewat TLS extension ikut dihitung di leaf
+		fingerprint.Certificate = fingerprint.Chain[0]
+		fingerprint.Certificate.SCTs += len(state.SignedCertificateTimestamps)
+
+		fingerprint.Validation = f.validateChain(state.PeerCertificates, host)
 	}
 
-	// Generate JA3 fingerprint (simplified)
-	fingerprint.JA3 = f.generateJA3(state)
+	// JA3/JA3S dari byte handshake yang direkam
+	f.computeJA3(recorder, fingerprint)
 
-	result["fingerprint"] = fingerprint
-	result["handshake_complete"] = state.HandshakeComplete
-	result["peer_certificates_count"] = len(state.PeerCertificates)
-	
-	return result
+	// ALPN, OCSP, key exchange, session ticket dan 0-RTT
+	f.inspectSession(conn, recorder, &state, cache, keyLog, fingerprint)
+
+	// Key log hanya untuk koneksi ini, jangan ikut ke koneksi resumption
+	tlsConfig.KeyLogWriter = nil
+
+	return fingerprint, tlsConfig, nil
 }
 
 // parseTarget memparse target menjadi host dan port
@@ -118,11 +289,32 @@ func (f *FingerprintSpoofer) parseTarget(target string) (string, string) {
 	return host, port
 }
 
+// createPermissiveTLSConfig membuat konfigurasi tetap yang menawarkan TLS 1.0-1.3 dan
+// semua cipher suite crypto/tls (termasuk CBC, 3DES dan RSA key exchange), sehingga
+// server lama atau yang hanya punya satu jenis cipher tetap terdeteksi sebagai TLS
+func (f *FingerprintSpoofer) createPermissiveTLSConfig(serverName string) *tls.Config {
+	var suites []uint16
+	for _, suite := range tls.CipherSuites() {
+		suites = append(suites, suite.ID)
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		suites = append(suites, suite.ID)
+	}
+
+	return &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true, // Chain divalidasi manual di validateChain
+		MinVersion:         tls.VersionTLS10,
+		MaxVersion:         tls.VersionTLS13,
+		CipherSuites:       suites,
+	}
+}
+
 // createRandomTLSConfig membuat konfigurasi TLS dengan fingerprint random
 func (f *FingerprintSpoofer) createRandomTLSConfig(serverName string) *tls.Config {
 	config := &tls.Config{
 		ServerName:         serverName,
-		InsecureSkipVerify: false,
+		InsecureSkipVerify: true, // Chain divalidasi manual di validateChain
 		MinVersion:         f.getRandomTLSVersion(),
 		MaxVersion:         tls.VersionTLS13,
 		CipherSuites:       f.getRandomCipherSuites(),
@@ -193,36 +385,32 @@ func (f *FingerprintSpoofer) getCipherSuiteString(suite uint16) string {
 	case tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384:
 		return "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384"
 	default:
+		if name, ok := cipherSuiteNames[suite]; ok {
+			return name
+		}
 		return fmt.Sprintf("Unknown (0x%x)", suite)
 	}
 }
 
-// generateJA3 menghasilkan JA3 fingerprint (simplified)
-func (f *FingerprintSpoofer) generateJA3(state tls.ConnectionState) string {
-	// JA3 format: TLSVersion,Ciphers,Extensions,EllipticCurves,EllipticCurvePointFormats
-	// Ini adalah implementasi sederhana
-	
-	version := fmt.Sprintf("%d", state.Version)
-	cipher := fmt.Sprintf("%d", state.CipherSuite)
-	
-	// Generate pseudo-random extensions untuk demo
-	extensions := "23-65281-10-11-35-16"
-	curves := "29-23-24"
-	pointFormats := "0"
-	
-	ja3String := fmt.Sprintf("%s,%s,%s,%s,%s", version, cipher, extensions, curves, pointFormats)
-	
-	return f.md5Hash(ja3String)[:16] // Simplified hash
-}
+// computeJA3 menghitung JA3 (ClientHello) dan JA3S (ServerHello) dari handshake yang direkam
+func (f *FingerprintSpoofer) computeJA3(recorder *recordingConn, fingerprint *TLSFingerprint) {
+	if body, err := extractHandshake(recorder.ClientBytes(), tlsHandshakeClientHello); err == nil {
+		if hello, err := parseClientHello(body); err == nil {
+			fingerprint.JA3String = hello.ja3String()
+			fingerprint.JA3 = ja3Hash(fingerprint.JA3String)
+		} else {
+			f.logger.Debug(fmt.Sprintf("ClientHello tidak dapat di-parse: %v", err))
+		}
+	}
 
-// md5Hash menghasilkan hash MD5 sederhana (untuk demo)
-func (f *FingerprintSpoofer) md5Hash(input string) string {
-	// Simplified hash implementation
-	hash := 0
-	for _, char := range input {
-		hash = hash*31 + int(char)
+	if body, err := extractHandshake(recorder.ServerBytes(), tlsHandshakeServerHello); err == nil {
+		if hello, err := parseServerHello(body); err == nil {
+			fingerprint.JA3SString = hello.ja3sString()
+			fingerprint.JA3S = ja3Hash(fingerprint.JA3SString)
+		} else {
+			f.logger.Debug(fmt.Sprintf("ServerHello tidak dapat di-parse: %v", err))
+		}
 	}
-	return fmt.Sprintf("%08x", hash)
 }
 
 // randomInt menghasilkan integer random
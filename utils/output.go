@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -173,17 +174,13 @@ func (o *OutputHandler) writeCSVData(writer *csv.Writer, results interface{}) er
 			}
 		}
 
-		// Extract TLS version
-		tlsVersion := ""
-		if result.TLSInfo != nil {
-			if fp, ok := result.TLSInfo["fingerprint"]; ok {
-				if fpMap, ok := fp.(map[string]interface{}); ok {
-					if ver, ok := fpMap["tls_version"]; ok {
-						tlsVersion = fmt.Sprintf("%v", ver)
-					}
-				}
-			}
+		// Extract TLS version per IP:port
+		var tlsVersions []string
+		for address, fingerprint := range result.TLS {
+			tlsVersions = append(tlsVersions, fmt.Sprintf("%s=%s", address, fingerprint.TLSVersion))
 		}
+		sort.Strings(tlsVersions)
+		tlsVersion := strings.Join(tlsVersions, ";")
 
 		// Write row
 		row := []string{
@@ -404,15 +401,42 @@ func (o *OutputHandler) generateHTMLReport(results interface{}) string {
 
 // ScanResult struct untuk type assertion (jika belum didefinisikan di tempat lain)
 type ScanResult struct {
-	Target      string                 `json:"target"`
-	IP          string                 `json:"ip,omitempty"`
-	Timestamp   time.Time              `json:"timestamp"`
-	DNSRecords  map[string][]string    `json:"dns_records,omitempty"`
-	OpenPorts   []int                  `json:"open_ports,omitempty"`
-	Services    map[int]string         `json:"services,omitempty"`
-	Traceroute  []string               `json:"traceroute,omitempty"`
-	CDNInfo     map[string]interface{} `json:"cdn_info,omitempty"`
-	TLSInfo     map[string]interface{} `json:"tls_info,omitempty"`
-	Error       string                 `json:"error,omitempty"`
-	ScanTime    time.Duration          `json:"scan_time"`
+	Target        string                 `json:"target"`
+	IP            string                 `json:"ip,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+	DNSRecords    map[string][]string    `json:"dns_records,omitempty"`
+	DNSErrors     map[string]string      `json:"dns_errors,omitempty"`
+	OpenPorts     []int                  `json:"open_ports,omitempty"`
+	Services      map[int]string         `json:"services,omitempty"`
+	Traceroute    []string               `json:"traceroute,omitempty"`
+	CDNInfo       map[string]interface{} `json:"cdn_info,omitempty"`
+	TLSInfo       map[string]interface{} `json:"tls_info,omitempty"` // Deprecated: gunakan TLS, diisi dari port TLS utama
+	Error         string                 `json:"error,omitempty"`
+	ScanTime      time.Duration          `json:"scan_time"`
+	DNSTime       time.Duration          `json:"dns_time"`
+	Wildcard      bool                   `json:"wildcard,omitempty"`
+	DNSSEC        *DNSSECResult          `json:"dnssec,omitempty"`
+	EmailSecurity *EmailSecurity         `json:"email_security,omitempty"`
+	Takeover      *TakeoverResult        `json:"takeover,omitempty"`
+	Delegation    *DelegationResult      `json:"delegation,omitempty"`
+
+	// Hasil TLS per IP:port: handshake langsung, enumerasi, dan upgrade STARTTLS
+	TLS      map[string]*TLSFingerprint `json:"tls,omitempty"`
+	TLSEnum  map[string]*TLSEnumResult  `json:"tls_enum,omitempty"`
+	STARTTLS map[string]*STARTTLSResult `json:"starttls,omitempty"`
+	JARM     map[string]*JARMResult     `json:"jarm,omitempty"`
+
+	// Miskonfigurasi TLS (sertifikat, chain, versi, cipher) di semua port TLS dan STARTTLS
+	TLSFindings []Finding `json:"tls_findings,omitempty"`
+
+	// SAN dari sertifikat yang belum menjadi target (kandidat target baru)
+	SANCandidates []string `json:"san_candidates,omitempty"`
+
+	ResolverComparison []*ResolverComparison `json:"resolver_comparison,omitempty"`
+
+	// Target discovery (subdomain enumeration, zone transfer)
+	DiscoveredFrom string              `json:"discovered_from,omitempty"`
+	Depth          int                 `json:"depth,omitempty"`
+	Subdomains     *EnumResult         `json:"subdomains,omitempty"`
+	ZoneTransfer   *ZoneTransferResult `json:"zone_transfer,omitempty"`
 }
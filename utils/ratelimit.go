@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter membatasi jumlah operasi per detik (aman dipakai concurrent)
+type RateLimiter struct {
+	interval time.Duration
+	mutex    sync.Mutex
+	next     time.Time
+}
+
+// NewRateLimiter membuat RateLimiter baru, nil jika perSecond <= 0 (tanpa batas)
+func NewRateLimiter(perSecond int) *RateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	return &RateLimiter{
+		interval: time.Second / time.Duration(perSecond),
+	}
+}
+
+// Wait menunggu sampai slot berikutnya tersedia
+func (r *RateLimiter) Wait() {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	now := time.Now()
+	if r.next.Before(now) {
+		r.next = now
+	}
+	wait := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+	r.mutex.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+//go:embed data/takeover-fingerprints.json
+var defaultTakeoverFingerprints []byte
+
+var (
+	builtinFingerprintsOnce sync.Once
+	builtinFingerprints     []TakeoverFingerprint
+)
+
+// maxCNAMEHops membatasi panjang CNAME chain yang diikuti
+const maxCNAMEHops = 16
+
+// TakeoverFingerprint menyimpan ciri service yang rawan subdomain takeover
+type TakeoverFingerprint struct {
+	Service     string   `json:"service"`
+	CNAME       []string `json:"cname"`
+	NXDomain    bool     `json:"nxdomain"`
+	Fingerprint []string `json:"fingerprint"`
+}
+
+// CNAMEChain menyimpan hasil mengikuti CNAME chain sampai target akhir
+type CNAMEChain struct {
+	Name     string   `json:"name"`
+	Chain    []string `json:"chain"`
+	Final    string   `json:"final"`
+	Loop     bool     `json:"loop,omitempty"`
+	NXDomain bool     `json:"nxdomain,omitempty"`
+}
+
+// TakeoverResult menyimpan hasil pengecekan dangling CNAME dan takeover
+type TakeoverResult struct {
+	CNAMEChain
+	Service    string    `json:"service,omitempty"`
+	Vulnerable bool      `json:"vulnerable"`
+	Evidence   string    `json:"evidence,omitempty"`
+	Findings   []Finding `json:"findings,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// LoadTakeoverFingerprints membaca data fingerprint takeover dari file JSON
+func (d *DNSResolver) LoadTakeoverFingerprints(filename string) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	var fingerprints []TakeoverFingerprint
+	if err := json.Unmarshal(content, &fingerprints); err != nil {
+		return fmt.Errorf("failed to parse takeover fingerprints: %v", err)
+	}
+
+	d.takeoverFingerprints = fingerprints
+	d.logger.Info(fmt.Sprintf("📋 Takeover fingerprints loaded: %d service dari %s", len(fingerprints), filename))
+	return nil
+}
+
+// FollowCNAMEChain mengikuti CNAME chain dengan proteksi loop
+func (d *DNSResolver) FollowCNAMEChain(name string) *CNAMEChain {
+	current := strings.TrimSuffix(strings.ToLower(name), ".")
+	chain := &CNAMEChain{
+		Name:  current,
+		Chain: make([]string, 0),
+	}
+
+	seen := map[string]bool{current: true}
+
+	for hop := 0; hop < maxCNAMEHops; hop++ {
+		targets, err := d.LookupCNAME(current)
+		if err != nil || len(targets) == 0 {
+			break
+		}
+
+		next := strings.ToLower(targets[0])
+		if seen[next] {
+			chain.Loop = true
+			break
+		}
+
+		seen[next] = true
+		chain.Chain = append(chain.Chain, next)
+		current = next
+	}
+
+	chain.Final = current
+	return chain
+}
+
+// CheckTakeover mengecek apakah CNAME chain berakhir di service yang dapat di-takeover
+func (d *DNSResolver) CheckTakeover(name string) *TakeoverResult {
+	result := &TakeoverResult{
+		CNAMEChain: *d.FollowCNAMEChain(name),
+		Findings:   make([]Finding, 0),
+		Timestamp:  time.Now(),
+	}
+
+	if len(result.Chain) == 0 {
+		return result
+	}
+
+	if result.Loop {
+		result.Findings = append(result.Findings, NewFinding(SeverityMedium, "dns",
+			"CNAME loop", strings.Join(append([]string{result.Name}, result.Chain...), " → ")))
+	}
+
+	result.NXDomain = d.isNXDomain(result.Final)
+
+	fingerprint := d.matchTakeoverService(result.Chain)
+	if fingerprint != nil {
+		result.Service = fingerprint.Service
+	}
+
+	switch {
+	case fingerprint != nil && fingerprint.NXDomain && result.NXDomain:
+		result.Vulnerable = true
+		result.Evidence = fmt.Sprintf("target %s NXDOMAIN", result.Final)
+	case fingerprint != nil && len(fingerprint.Fingerprint) > 0 && !result.NXDomain:
+		if evidence := d.matchTakeoverResponse(result.Name, fingerprint.Fingerprint); evidence != "" {
+			result.Vulnerable = true
+			result.Evidence = evidence
+		}
+	}
+
+	if result.Vulnerable {
+		result.Findings = append(result.Findings, NewFinding(SeverityHigh, "dns",
+			"Subdomain takeover: "+result.Service, fmt.Sprintf("%s → %s: %s", result.Name, result.Final, result.Evidence)))
+		d.logger.Warn(fmt.Sprintf("🚨 Potensi subdomain takeover %s (%s)", result.Name, result.Service))
+	} else if result.NXDomain {
+		// Target NXDOMAIN bisa didaftarkan ulang oleh pihak lain meski service-nya tidak dikenal
+		result.Findings = append(result.Findings, NewFinding(SeverityHigh, "dns",
+			"Dangling CNAME", fmt.Sprintf("%s → %s (NXDOMAIN)", result.Name, result.Final)))
+	}
+
+	return result
+}
+
+// matchTakeoverService mencari fingerprint service yang cocok dengan salah satu hop CNAME
+func (d *DNSResolver) matchTakeoverService(chain []string) *TakeoverFingerprint {
+	fingerprints := d.getTakeoverFingerprints()
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		for idx := range fingerprints {
+			for _, pattern := range fingerprints[idx].CNAME {
+				if strings.Contains(chain[i], strings.ToLower(pattern)) {
+					return &fingerprints[idx]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchTakeoverResponse mengambil halaman HTTP dan mencari string khas provider
+func (d *DNSResolver) matchTakeoverResponse(name string, fingerprints []string) string {
+	for _, scheme := range []string{"http", "https"} {
+		url := fmt.Sprintf("%s://%s/", scheme, name)
+		d.logger.LogRequest("GET", url)
+
+		resp, err := d.httpClient.Get(url)
+		if err != nil {
+			continue
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256*1024))
+		resp.Body.Close()
+
+		for _, fingerprint := range fingerprints {
+			if strings.Contains(string(body), fingerprint) {
+				return fmt.Sprintf("HTTP %d %s berisi %q", resp.StatusCode, url, fingerprint)
+			}
+		}
+	}
+	return ""
+}
+
+// isNXDomain mengecek apakah nama mengembalikan NXDOMAIN
+func (d *DNSResolver) isNXDomain(name string) bool {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), dns.TypeA)
+	msg.RecursionDesired = true
+
+	resp, err := d.exchange(msg)
+	return err == nil && resp.Rcode == dns.RcodeNameError
+}
+
+// getTakeoverFingerprints mengembalikan fingerprint dari file atau data bawaan
+func (d *DNSResolver) getTakeoverFingerprints() []TakeoverFingerprint {
+	if len(d.takeoverFingerprints) > 0 {
+		return d.takeoverFingerprints
+	}
+
+	builtinFingerprintsOnce.Do(func() {
+		if err := json.Unmarshal(defaultTakeoverFingerprints, &builtinFingerprints); err != nil {
+			d.logger.Error(fmt.Sprintf("Takeover fingerprints bawaan tidak valid: %v", err))
+		}
+	})
+	return builtinFingerprints
+}
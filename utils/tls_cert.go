@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// oidSCTList adalah OID extension embedded SCT (RFC 6962)
+var oidSCTList = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// ChainValidation menyimpan hasil validasi certificate chain
+type ChainValidation struct {
+	Valid         bool     `json:"valid"`
+	Roots         string   `json:"roots"`
+	Reason        string   `json:"reason,omitempty"`
+	HostnameMatch bool     `json:"hostname_match"`
+	Error         string   `json:"error,omitempty"`
+	VerifiedChain []string `json:"verified_chain,omitempty"`
+}
+
+// LoadCABundle membaca CA bundle PEM sebagai pengganti system roots untuk validasi chain
+func (f *FingerprintSpoofer) LoadCABundle(filename string) error {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return fmt.Errorf("no PEM certificates found in %s", filename)
+	}
+
+	f.roots = pool
+	f.rootsSource = filename
+	f.logger.Info(fmt.Sprintf("📋 CA bundle loaded dari %s", filename))
+	return nil
+}
+
+// validateChain memvalidasi chain terhadap system roots atau CA bundle dan hostname
+func (f *FingerprintSpoofer) validateChain(certs []*x509.Certificate, host string) *ChainValidation {
+	validation := &ChainValidation{Roots: "system"}
+	if f.roots != nil {
+		validation.Roots = f.rootsSource
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// Hostname dicek terpisah dari chain agar masalah trust tidak tertutup hostname mismatch
+	hostnameErr := certs[0].VerifyHostname(host)
+	validation.HostnameMatch = hostnameErr == nil
+
+	chains, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         f.roots,
+		Intermediates: intermediates,
+		CurrentTime:   time.Now(),
+	})
+	if err != nil {
+		validation.Reason = classifyVerifyError(err, certs[0])
+		validation.Error = err.Error()
+		return validation
+	}
+
+	for _, cert := range chains[0] {
+		validation.VerifiedChain = append(validation.VerifiedChain, cert.Subject.String())
+	}
+
+	if hostnameErr != nil {
+		validation.Reason = classifyVerifyError(hostnameErr, certs[0])
+		validation.Error = hostnameErr.Error()
+		return validation
+	}
+
+	validation.Valid = true
+	return validation
+}
+
+// classifyVerifyError mengubah error x509 menjadi alasan kegagalan yang spesifik
+func classifyVerifyError(err error, leaf *x509.Certificate) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	var systemRoots x509.SystemRootsError
+	var insecure x509.InsecureAlgorithmError
+	var constraint x509.ConstraintViolationError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		if isSelfSigned(leaf) {
+			return "self_signed"
+		}
+		return "unknown_authority"
+	case errors.As(err, &invalid):
+		switch invalid.Reason {
+		case x509.Expired:
+			if time.Now().Before(invalid.Cert.NotBefore) {
+				return "not_yet_valid"
+			}
+			return "expired"
+		case x509.NotAuthorizedToSign:
+			return "not_authorized_to_sign"
+		case x509.CANotAuthorizedForThisName, x509.CANotAuthorizedForExtKeyUsage:
+			return "name_constraints"
+		case x509.TooManyIntermediates:
+			return "too_many_intermediates"
+		case x509.IncompatibleUsage:
+			return "incompatible_usage"
+		case x509.NameMismatch:
+			return "name_mismatch"
+		case x509.TooManyConstraints:
+			return "too_many_constraints"
+		default:
+			return "invalid"
+		}
+	case errors.As(err, &hostname):
+		return "hostname_mismatch"
+	case errors.As(err, &systemRoots):
+		return "system_roots_unavailable"
+	case errors.As(err, &insecure):
+		return "insecure_algorithm"
+	case errors.As(err, &constraint):
+		return "constraint_violation"
+	default:
+		return "unknown"
+	}
+}
+
+// describeCertificate mengekstrak detail sertifikat untuk laporan
+func describeCertificate(cert *x509.Certificate) *CertificateInfo {
+	sha256Sum := sha256.Sum256(cert.Raw)
+	sha1Sum := sha1.Sum(cert.Raw)
+
+	info := &CertificateInfo{
+		Subject:               cert.Subject.String(),
+		Issuer:                cert.Issuer.String(),
+		NotBefore:             cert.NotBefore,
+		NotAfter:              cert.NotAfter,
+		SerialNumber:          cert.SerialNumber.String(),
+		Fingerprint:           hex.EncodeToString(sha256Sum[:]),
+		FingerprintSHA1:       hex.EncodeToString(sha1Sum[:]),
+		DaysUntilExpiry:       int(math.Floor(time.Until(cert.NotAfter).Hours() / 24)),
+		DNSNames:              cert.DNSNames,
+		EmailAddresses:        cert.EmailAddresses,
+		SignatureAlgorithm:    cert.SignatureAlgorithm.String(),
+		IsCA:                  cert.IsCA,
+		SelfSigned:            isSelfSigned(cert),
+		IssuingCertificateURL: cert.IssuingCertificateURL,
+		OCSPServers:           cert.OCSPServer,
+		CRLDistributionPoints: cert.CRLDistributionPoints,
+		SCTs:                  countEmbeddedSCTs(cert),
+	}
+
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		info.URIs = append(info.URIs, uri.String())
+	}
+
+	if cert.BasicConstraintsValid && (cert.MaxPathLen > 0 || cert.MaxPathLenZero) {
+		pathLen := cert.MaxPathLen
+		info.MaxPathLen = &pathLen
+	}
+
+	info.KeyAlgorithm, info.KeySize = publicKeyInfo(cert)
+
+	return info
+}
+
+// publicKeyInfo mengembalikan algoritma dan ukuran public key sertifikat
+func publicKeyInfo(cert *x509.Certificate) (string, int) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA " + key.Curve.Params().Name, key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return cert.PublicKeyAlgorithm.String(), 0
+	}
+}
+
+// countEmbeddedSCTs menghitung SCT di extension sertifikat
+func countEmbeddedSCTs(cert *x509.Certificate) int {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSCTList) {
+			continue
+		}
+
+		var list []byte
+		if _, err := asn1.Unmarshal(ext.Value, &list); err != nil {
+			return 0
+		}
+
+		// SignedCertificateTimestampList: uint16 total, lalu entry uint16 length + data
+		r := &byteReader{data: list}
+		entries := &byteReader{data: r.bytes(int(r.uint16()))}
+		count := 0
+		for entries.remaining() >= 2 {
+			entries.skip(int(entries.uint16()))
+			if entries.err != nil {
+				break
+			}
+			count++
+		}
+		return count
+	}
+	return 0
+}
+
+// isSelfSigned mengecek apakah sertifikat ditandatangani oleh dirinya sendiri
+func isSelfSigned(cert *x509.Certificate) bool {
+	return cert.Subject.String() == cert.Issuer.String() && cert.CheckSignatureFrom(cert) == nil
+}
@@ -0,0 +1,681 @@
+package utils
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tlsEnumTimeout adalah timeout per koneksi probe ClientHello
+const tlsEnumTimeout = 5 * time.Second
+
+// Versi protokol SSL/TLS di wire format
+const (
+	versionSSL30 uint16 = 0x0300
+	versionTLS10 uint16 = 0x0301
+	versionTLS11 uint16 = 0x0302
+	versionTLS12 uint16 = 0x0303
+	versionTLS13 uint16 = 0x0304
+)
+
+// TLS record dan extension tambahan untuk ClientHello mentah
+const (
+	tlsRecordAlert                 = 21
+	tlsExtServerName               = 0
+	tlsExtSignatureAlgs            = 13
+	tlsExtSupportedVersions        = 43
+	tlsExtKeyShare                 = 51
+	tlsGroupX25519          uint16 = 0x001d
+)
+
+// enumVersions adalah urutan versi yang dienumerasi
+var enumVersions = []uint16{versionSSL30, versionTLS10, versionTLS11, versionTLS12, versionTLS13}
+
+// enumGroups adalah supported_groups yang ditawarkan saat probing
+var enumGroups = []uint16{tlsGroupX25519, 0x0017, 0x0018, 0x0019}
+
+// enumSignatureAlgorithms adalah signature_algorithms yang ditawarkan saat probing
+var enumSignatureAlgorithms = []uint16{
+	0x0403, 0x0503, 0x0603, 0x0804, 0x0805, 0x0806,
+	0x0401, 0x0501, 0x0601, 0x0203, 0x0201,
+}
+
+// cipherSuiteNames memetakan ID cipher suite IANA ke nama
+var cipherSuiteNames = map[uint16]string{
+	// TLS 1.3
+	0x1301: "TLS_AES_128_GCM_SHA256",
+	0x1302: "TLS_AES_256_GCM_SHA384",
+	0x1303: "TLS_CHACHA20_POLY1305_SHA256",
+	0x1304: "TLS_AES_128_CCM_SHA256",
+	0x1305: "TLS_AES_128_CCM_8_SHA256",
+
+	// ECDHE
+	0xc02b: "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+	0xc02c: "TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	0xc02f: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+	0xc030: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	0xcca8: "TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	0xcca9: "TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+	0xc0ac: "TLS_ECDHE_ECDSA_WITH_AES_128_CCM",
+	0xc0ad: "TLS_ECDHE_ECDSA_WITH_AES_256_CCM",
+	0xc023: "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256",
+	0xc024: "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA384",
+	0xc027: "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256",
+	0xc028: "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA384",
+	0xc009: "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA",
+	0xc00a: "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA",
+	0xc013: "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+	0xc014: "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA",
+	0xc008: "TLS_ECDHE_ECDSA_WITH_3DES_EDE_CBC_SHA",
+	0xc012: "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA",
+	0xc007: "TLS_ECDHE_ECDSA_WITH_RC4_128_SHA",
+	0xc011: "TLS_ECDHE_RSA_WITH_RC4_128_SHA",
+	0xc006: "TLS_ECDHE_ECDSA_WITH_NULL_SHA",
+	0xc010: "TLS_ECDHE_RSA_WITH_NULL_SHA",
+
+	// DHE
+	0xccaa: "TLS_DHE_RSA_WITH_CHACHA20_POLY1305_SHA256",
+	0x009e: "TLS_DHE_RSA_WITH_AES_128_GCM_SHA256",
+	0x009f: "TLS_DHE_RSA_WITH_AES_256_GCM_SHA384",
+	0x0067: "TLS_DHE_RSA_WITH_AES_128_CBC_SHA256",
+	0x006b: "TLS_DHE_RSA_WITH_AES_256_CBC_SHA256",
+	0x0033: "TLS_DHE_RSA_WITH_AES_128_CBC_SHA",
+	0x0039: "TLS_DHE_RSA_WITH_AES_256_CBC_SHA",
+	0x0045: "TLS_DHE_RSA_WITH_CAMELLIA_128_CBC_SHA",
+	0x0088: "TLS_DHE_RSA_WITH_CAMELLIA_256_CBC_SHA",
+	0x0016: "TLS_DHE_RSA_WITH_3DES_EDE_CBC_SHA",
+	0x0015: "TLS_DHE_RSA_WITH_DES_CBC_SHA",
+	0x0014: "TLS_DHE_RSA_EXPORT_WITH_DES40_CBC_SHA",
+	0x0032: "TLS_DHE_DSS_WITH_AES_128_CBC_SHA",
+	0x0038: "TLS_DHE_DSS_WITH_AES_256_CBC_SHA",
+	0x0013: "TLS_DHE_DSS_WITH_3DES_EDE_CBC_SHA",
+
+	// RSA key exchange
+	0x009c: "TLS_RSA_WITH_AES_128_GCM_SHA256",
+	0x009d: "TLS_RSA_WITH_AES_256_GCM_SHA384",
+	0x003c: "TLS_RSA_WITH_AES_128_CBC_SHA256",
+	0x003d: "TLS_RSA_WITH_AES_256_CBC_SHA256",
+	0x002f: "TLS_RSA_WITH_AES_128_CBC_SHA",
+	0x0035: "TLS_RSA_WITH_AES_256_CBC_SHA",
+	0x0041: "TLS_RSA_WITH_CAMELLIA_128_CBC_SHA",
+	0x0084: "TLS_RSA_WITH_CAMELLIA_256_CBC_SHA",
+	0x0096: "TLS_RSA_WITH_SEED_CBC_SHA",
+	0x0007: "TLS_RSA_WITH_IDEA_CBC_SHA",
+	0x000a: "TLS_RSA_WITH_3DES_EDE_CBC_SHA",
+	0x0009: "TLS_RSA_WITH_DES_CBC_SHA",
+	0x0005: "TLS_RSA_WITH_RC4_128_SHA",
+	0x0004: "TLS_RSA_WITH_RC4_128_MD5",
+	0x0003: "TLS_RSA_EXPORT_WITH_RC4_40_MD5",
+	0x0006: "TLS_RSA_EXPORT_WITH_RC2_CBC_40_MD5",
+	0x0008: "TLS_RSA_EXPORT_WITH_DES40_CBC_SHA",
+	0x0001: "TLS_RSA_WITH_NULL_MD5",
+	0x0002: "TLS_RSA_WITH_NULL_SHA",
+	0x003b: "TLS_RSA_WITH_NULL_SHA256",
+
+	// Anonymous
+	0x0018: "TLS_DH_anon_WITH_RC4_128_MD5",
+	0x001b: "TLS_DH_anon_WITH_3DES_EDE_CBC_SHA",
+	0x0034: "TLS_DH_anon_WITH_AES_128_CBC_SHA",
+	0x003a: "TLS_DH_anon_WITH_AES_256_CBC_SHA",
+	0x00a6: "TLS_DH_anon_WITH_AES_128_GCM_SHA256",
+	0xc016: "TLS_ECDH_anon_WITH_RC4_128_SHA",
+	0xc018: "TLS_ECDH_anon_WITH_AES_128_CBC_SHA",
+	0xc019: "TLS_ECDH_anon_WITH_AES_256_CBC_SHA",
+}
+
+// Strength cipher suite
+const (
+	CipherStrong   = "strong"
+	CipherWeak     = "weak"
+	CipherInsecure = "insecure"
+)
+
+// CipherSuiteSupport menyimpan satu cipher suite yang diterima server
+type CipherSuiteSupport struct {
+	ID       uint16   `json:"id"`
+	Name     string   `json:"name"`
+	Strength string   `json:"strength"`
+	Issues   []string `json:"issues,omitempty"`
+}
+
+// TLSProtocolSupport menyimpan dukungan server untuk satu versi protokol
+type TLSProtocolSupport struct {
+	Version          string               `json:"version"`
+	Supported        bool                 `json:"supported"`
+	ServerPreference bool                 `json:"server_preference,omitempty"`
+	Ciphers          []CipherSuiteSupport `json:"ciphers,omitempty"`
+}
+
+// TLSEnumResult menyimpan matriks versi dan cipher suite yang didukung server
+type TLSEnumResult struct {
+	Target    string               `json:"target"`
+	Address   string               `json:"address"`
+	Protocols []TLSProtocolSupport `json:"protocols"`
+	Error     string               `json:"error,omitempty"`
+	Findings  []Finding            `json:"findings,omitempty"`
+	Timestamp time.Time            `json:"timestamp"`
+	Duration  string               `json:"duration"`
+}
+
+// clientHelloSpec mendeskripsikan ClientHello mentah yang akan dikirim
+type clientHelloSpec struct {
+	Version           uint16
+	CipherSuites      []uint16
+	ServerName        string
+	SupportedVersions []uint16
+	NoExtensions      bool
+
+	// RecordVersion menimpa versi record layer (default TLS 1.0, atau SSLv3)
+	RecordVersion uint16
+	// Extensions menimpa blok extension hasil buildHelloExtensions (dipakai probe JARM)
+	Extensions []byte
+}
+
+// helloResponse menyimpan respons server terhadap ClientHello mentah
+type helloResponse struct {
+	Hello   *helloMessage
+	Alert   uint8
+	Alerted bool
+}
+
+// EnumerateTLS menguji setiap versi protokol dan cipher suite secara deterministik
+// dengan ClientHello mentah, termasuk SSLv3/TLS 1.0/1.1 yang tidak didukung crypto/tls
+func (f *FingerprintSpoofer) EnumerateTLS(target string) *TLSEnumResult {
+	host, port := f.parseTarget(target)
+	if port == "" {
+		port = "443"
+	}
+
+	result := f.EnumerateTLSAddress(net.JoinHostPort(host, port), host)
+	result.Target = target
+	return result
+}
+
+// EnumerateTLSAddress mengenumerasi address (IP:port) dengan SNI dari hostname asli
+func (f *FingerprintSpoofer) EnumerateTLSAddress(address, host string) *TLSEnumResult {
+	start := time.Now()
+
+	result := &TLSEnumResult{
+		Target:    host,
+		Address:   address,
+		Timestamp: start,
+	}
+
+	serverName := host
+	if net.ParseIP(host) != nil {
+		serverName = ""
+	}
+
+	result.Protocols = make([]TLSProtocolSupport, len(enumVersions))
+
+	var wg sync.WaitGroup
+	var errMutex sync.Mutex
+	var lastErr error
+
+	for i, version := range enumVersions {
+		wg.Add(1)
+		go func(i int, version uint16) {
+			defer wg.Done()
+
+			support, err := f.enumerateVersion(result.Address, serverName, version)
+			result.Protocols[i] = *support
+			if err != nil {
+				errMutex.Lock()
+				lastErr = err
+				errMutex.Unlock()
+			}
+		}(i, version)
+	}
+	wg.Wait()
+
+	supported := false
+	for _, protocol := range result.Protocols {
+		supported = supported || protocol.Supported
+	}
+	if !supported && lastErr != nil {
+		result.Error = lastErr.Error()
+	}
+
+	result.assess()
+	result.Duration = time.Since(start).String()
+
+	f.logger.Debug(fmt.Sprintf("TLS enumeration %s selesai dalam %s", result.Address, result.Duration))
+	return result
+}
+
+// enumerateVersion mencari semua cipher suite yang diterima untuk satu versi dengan
+// eliminasi: cipher yang dipilih server dibuang lalu ClientHello dikirim ulang
+func (f *FingerprintSpoofer) enumerateVersion(address, serverName string, version uint16) (*TLSProtocolSupport, error) {
+	support := &TLSProtocolSupport{Version: tlsVersionName(version)}
+
+	remaining := enumCipherSuites(version)
+	var accepted []uint16
+
+	for len(remaining) > 0 {
+		cipher, err := probeCipher(address, serverName, version, remaining, tlsEnumTimeout)
+		if err != nil {
+			if len(accepted) == 0 {
+				return support, err
+			}
+			break
+		}
+		if cipher == 0 {
+			break
+		}
+
+		accepted = append(accepted, cipher)
+		remaining = removeUint16(remaining, cipher)
+	}
+
+	if len(accepted) == 0 {
+		return support, nil
+	}
+	support.Supported = true
+
+	// Server memaksakan urutannya jika pilihan tetap sama saat urutan client dibalik
+	if len(accepted) > 1 {
+		reversed := make([]uint16, len(accepted))
+		for i, cipher := range accepted {
+			reversed[len(accepted)-1-i] = cipher
+		}
+		if cipher, err := probeCipher(address, serverName, version, reversed, tlsEnumTimeout); err == nil {
+			support.ServerPreference = cipher == accepted[0]
+		}
+	}
+
+	for _, cipher := range accepted {
+		support.Ciphers = append(support.Ciphers, describeCipherSuite(cipher))
+	}
+
+	return support, nil
+}
+
+// probeCipher mengirim ClientHello untuk versi dan cipher tertentu lalu mengembalikan
+// cipher yang dipilih server, atau 0 jika server menolak
+func probeCipher(address, serverName string, version uint16, ciphers []uint16, timeout time.Duration) (uint16, error) {
+	spec := &clientHelloSpec{
+		Version:      version,
+		CipherSuites: ciphers,
+		ServerName:   serverName,
+		NoExtensions: version == versionSSL30,
+	}
+	if version == versionTLS13 {
+		spec.Version = versionTLS12
+		spec.SupportedVersions = []uint16{versionTLS13}
+	}
+
+	response, err := exchangeClientHello(address, buildClientHello(spec), timeout)
+	if err != nil {
+		return 0, err
+	}
+	if response.Hello == nil {
+		return 0, nil
+	}
+
+	if negotiatedVersion(response.Hello) != version {
+		return 0, nil
+	}
+
+	cipher := response.Hello.CipherSuites[0]
+	if !containsUint16(ciphers, cipher) {
+		return 0, nil
+	}
+
+	return cipher, nil
+}
+
+// probeLegacyVersions mengirim satu ClientHello per versi deprecated dengan semua cipher
+// suite versi tersebut dan mengembalikan versi yang diterima server
+func probeLegacyVersions(address, serverName string, timeout time.Duration) []string {
+	if net.ParseIP(serverName) != nil {
+		serverName = ""
+	}
+
+	legacy := []uint16{versionSSL30, versionTLS10, versionTLS11}
+	accepted := make([]bool, len(legacy))
+
+	var wg sync.WaitGroup
+	for i, version := range legacy {
+		wg.Add(1)
+		go func(i int, version uint16) {
+			defer wg.Done()
+
+			cipher, err := probeCipher(address, serverName, version, enumCipherSuites(version), timeout)
+			accepted[i] = err == nil && cipher != 0
+		}(i, version)
+	}
+	wg.Wait()
+
+	var versions []string
+	for i, version := range legacy {
+		if accepted[i] {
+			versions = append(versions, tlsVersionName(version))
+		}
+	}
+	return versions
+}
+
+// negotiatedVersion mengembalikan versi dari ServerHello, termasuk supported_versions TLS 1.3
+func negotiatedVersion(hello *helloMessage) uint16 {
+	if data, ok := hello.ExtData[tlsExtSupportedVersions]; ok && len(data) == 2 {
+		return uint16(data[0])<<8 | uint16(data[1])
+	}
+	return hello.Version
+}
+
+// buildClientHello menyusun record ClientHello mentah dari spec
+func buildClientHello(spec *clientHelloSpec) []byte {
+	random := make([]byte, 32)
+	rand.Read(random)
+	sessionID := make([]byte, 32)
+	rand.Read(sessionID)
+
+	var body []byte
+	body = appendUint16(body, spec.Version)
+	body = append(body, random...)
+	body = append(body, byte(len(sessionID)))
+	body = append(body, sessionID...)
+
+	body = appendUint16(body, uint16(len(spec.CipherSuites)*2))
+	for _, cipher := range spec.CipherSuites {
+		body = appendUint16(body, cipher)
+	}
+	body = append(body, 1, 0) // compression: null
+
+	if !spec.NoExtensions {
+		extensions := spec.Extensions
+		if extensions == nil {
+			extensions = buildHelloExtensions(spec)
+		}
+		body = appendUint16(body, uint16(len(extensions)))
+		body = append(body, extensions...)
+	}
+
+	handshake := []byte{tlsHandshakeClientHello, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))}
+	handshake = append(handshake, body...)
+
+	// Record version memakai TLS 1.0 (atau SSLv3) agar diterima server lama
+	recordVersion := versionTLS10
+	if spec.Version == versionSSL30 {
+		recordVersion = versionSSL30
+	}
+	if spec.RecordVersion != 0 {
+		recordVersion = spec.RecordVersion
+	}
+
+	record := []byte{tlsRecordHandshake}
+	record = appendUint16(record, recordVersion)
+	record = appendUint16(record, uint16(len(handshake)))
+	return append(record, handshake...)
+}
+
+// buildHelloExtensions menyusun extension ClientHello untuk probing
+func buildHelloExtensions(spec *clientHelloSpec) []byte {
+	var extensions []byte
+
+	if spec.ServerName != "" {
+		name := []byte(spec.ServerName)
+		var data []byte
+		data = appendUint16(data, uint16(len(name)+3))
+		data = append(data, 0) // host_name
+		data = appendUint16(data, uint16(len(name)))
+		data = append(data, name...)
+		extensions = appendExtension(extensions, tlsExtServerName, data)
+	}
+
+	var groups []byte
+	groups = appendUint16(groups, uint16(len(enumGroups)*2))
+	for _, group := range enumGroups {
+		groups = appendUint16(groups, group)
+	}
+	extensions = appendExtension(extensions, tlsExtSupportedGroups, groups)
+	extensions = appendExtension(extensions, tlsExtPointFormats, []byte{1, 0})
+
+	var signatures []byte
+	signatures = appendUint16(signatures, uint16(len(enumSignatureAlgorithms)*2))
+	for _, algorithm := range enumSignatureAlgorithms {
+		signatures = appendUint16(signatures, algorithm)
+	}
+	extensions = appendExtension(extensions, tlsExtSignatureAlgs, signatures)
+
+	if len(spec.SupportedVersions) > 0 {
+		versions := []byte{byte(len(spec.SupportedVersions) * 2)}
+		for _, version := range spec.SupportedVersions {
+			versions = appendUint16(versions, version)
+		}
+		extensions = appendExtension(extensions, tlsExtSupportedVersions, versions)
+
+		// Key share X25519; public key random cukup karena handshake tidak diselesaikan
+		key := make([]byte, 32)
+		rand.Read(key)
+		var share []byte
+		share = appendUint16(share, tlsGroupX25519)
+		share = appendUint16(share, uint16(len(key)))
+		share = append(share, key...)
+		var keyShare []byte
+		keyShare = appendUint16(keyShare, uint16(len(share)))
+		keyShare = append(keyShare, share...)
+		extensions = appendExtension(extensions, tlsExtKeyShare, keyShare)
+	}
+
+	return extensions
+}
+
+// exchangeClientHello mengirim ClientHello mentah dan membaca ServerHello atau alert
+func exchangeClientHello(address string, hello []byte, timeout time.Duration) (*helloResponse, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(hello); err != nil {
+		return nil, err
+	}
+
+	var stream []byte
+	buf := make([]byte, 4096)
+	for len(stream) < maxRecordedHandshake {
+		n, err := conn.Read(buf)
+		stream = append(stream, buf[:n]...)
+
+		if len(stream) >= 7 && stream[0] == tlsRecordAlert {
+			return &helloResponse{Alert: stream[6], Alerted: true}, nil
+		}
+		if body, parseErr := extractHandshake(stream, tlsHandshakeServerHello); parseErr == nil {
+			serverHello, parseErr := parseServerHello(body)
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			return &helloResponse{Hello: serverHello}, nil
+		}
+
+		if err != nil {
+			// Koneksi ditutup tanpa ServerHello berarti ClientHello ditolak
+			var netErr net.Error
+			if errors.Is(err, io.EOF) || (errors.As(err, &netErr) && !netErr.Timeout()) {
+				return &helloResponse{}, nil
+			}
+			return nil, err
+		}
+	}
+
+	return &helloResponse{}, nil
+}
+
+// assess menghasilkan findings untuk versi dan cipher suite yang lemah
+func (r *TLSEnumResult) assess() {
+	supported := make(map[string]bool)
+	for _, protocol := range r.Protocols {
+		supported[protocol.Version] = protocol.Supported
+	}
+
+	if r.Error != "" {
+		return
+	}
+
+	if supported["SSL 3.0"] {
+		r.addFinding(SeverityHigh, "SSLv3 didukung", "rentan POODLE, nonaktifkan SSLv3")
+	}
+	if supported["TLS 1.0"] {
+		r.addFinding(SeverityMedium, "TLS 1.0 didukung", "protokol deprecated (RFC 8996)")
+	}
+	if supported["TLS 1.1"] {
+		r.addFinding(SeverityMedium, "TLS 1.1 didukung", "protokol deprecated (RFC 8996)")
+	}
+	if !supported["TLS 1.2"] && !supported["TLS 1.3"] {
+		r.addFinding(SeverityHigh, "TLS 1.2/1.3 tidak didukung", "server hanya menerima protokol lama")
+	} else if !supported["TLS 1.3"] {
+		r.addFinding(SeverityInfo, "TLS 1.3 tidak didukung", "")
+	}
+
+	for _, protocol := range r.Protocols {
+		if !protocol.Supported {
+			continue
+		}
+
+		var insecure, weak []string
+		for _, cipher := range protocol.Ciphers {
+			switch cipher.Strength {
+			case CipherInsecure:
+				insecure = append(insecure, cipher.Name)
+			case CipherWeak:
+				weak = append(weak, cipher.Name)
+			}
+		}
+
+		if len(insecure) > 0 {
+			r.addFinding(SeverityHigh, fmt.Sprintf("Cipher suite insecure di %s", protocol.Version),
+				strings.Join(insecure, ", "))
+		}
+		if len(weak) > 0 {
+			r.addFinding(SeverityLow, fmt.Sprintf("Cipher suite lemah (CBC/3DES/tanpa forward secrecy) di %s", protocol.Version),
+				strings.Join(weak, ", "))
+		}
+		if len(protocol.Ciphers) > 1 && !protocol.ServerPreference && protocol.Version != "TLS 1.3" {
+			r.addFinding(SeverityLow, fmt.Sprintf("Server tidak memaksakan urutan cipher di %s", protocol.Version),
+				"cipher dipilih berdasarkan urutan client")
+		}
+	}
+}
+
+// addFinding menambahkan finding TLS ke hasil enumerasi
+func (r *TLSEnumResult) addFinding(severity, title, detail string) {
+	r.Findings = append(r.Findings, NewFinding(severity, "tls", title, detail))
+}
+
+// enumCipherSuites mengembalikan cipher suite kandidat untuk versi tertentu
+func enumCipherSuites(version uint16) []uint16 {
+	var ciphers []uint16
+	for id := range cipherSuiteNames {
+		isTLS13 := id>>8 == 0x13
+		if isTLS13 == (version == versionTLS13) {
+			ciphers = append(ciphers, id)
+		}
+	}
+
+	// Urutan ID agar ClientHello deterministik
+	sort.Slice(ciphers, func(i, j int) bool { return ciphers[i] < ciphers[j] })
+
+	return ciphers
+}
+
+// describeCipherSuite mengklasifikasikan cipher suite berdasarkan namanya
+func describeCipherSuite(id uint16) CipherSuiteSupport {
+	name, ok := cipherSuiteNames[id]
+	if !ok {
+		name = fmt.Sprintf("Unknown (0x%04x)", id)
+	}
+
+	cipher := CipherSuiteSupport{ID: id, Name: name, Strength: CipherStrong}
+
+	checks := []struct {
+		pattern  string
+		issue    string
+		strength string
+	}{
+		{"_NULL_", "null_cipher", CipherInsecure},
+		{"_anon_", "anonymous", CipherInsecure},
+		{"_EXPORT_", "export", CipherInsecure},
+		{"_RC4_", "rc4", CipherInsecure},
+		{"_RC2_", "rc2", CipherInsecure},
+		{"_DES_CBC_", "des", CipherInsecure},
+		{"_DES40_", "des", CipherInsecure},
+		{"_MD5", "md5", CipherInsecure},
+		{"_3DES_", "3des", CipherWeak},
+		{"_IDEA_", "idea", CipherWeak},
+		{"_SEED_", "seed", CipherWeak},
+		{"_CBC_", "cbc", CipherWeak},
+		{"TLS_RSA_", "rsa_kex", CipherWeak},
+	}
+
+	for _, check := range checks {
+		if !strings.Contains(name, check.pattern) {
+			continue
+		}
+		cipher.Issues = append(cipher.Issues, check.issue)
+		if cipher.Strength != CipherInsecure {
+			cipher.Strength = check.strength
+		}
+	}
+
+	return cipher
+}
+
+// tlsVersionName mengkonversi versi wire ke nama protokol
+func tlsVersionName(version uint16) string {
+	switch version {
+	case versionSSL30:
+		return "SSL 3.0"
+	case versionTLS10:
+		return "TLS 1.0"
+	case versionTLS11:
+		return "TLS 1.1"
+	case versionTLS12:
+		return "TLS 1.2"
+	case versionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("Unknown (0x%04x)", version)
+	}
+}
+
+// appendExtension menambahkan satu extension (type, length, data)
+func appendExtension(buf []byte, extType uint16, data []byte) []byte {
+	buf = appendUint16(buf, extType)
+	buf = appendUint16(buf, uint16(len(data)))
+	return append(buf, data...)
+}
+
+// appendUint16 menambahkan nilai big-endian dua byte
+func appendUint16(buf []byte, value uint16) []byte {
+	return append(buf, byte(value>>8), byte(value))
+}
+
+// containsUint16 mengecek apakah value ada di values
+func containsUint16(values []uint16, value uint16) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// removeUint16 mengembalikan values tanpa value
+func removeUint16(values []uint16, value uint16) []uint16 {
+	out := make([]uint16, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"sort"
+	"time"
+)
+
+// Status expiry sertifikat, mengikuti konvensi plugin monitoring (Nagios/Icinga)
+const (
+	ExpiryOK       = "OK"
+	ExpiryWarning  = "WARNING"
+	ExpiryCritical = "CRITICAL"
+	ExpiryUnknown  = "UNKNOWN"
+)
+
+// Default ambang hari untuk monitoring expiry
+const (
+	DefaultExpiryWarnDays = 30
+	DefaultExpiryCritDays = 7
+)
+
+// CertificateExpiry menyimpan sertifikat leaf satu endpoint TLS beserta status expiry
+type CertificateExpiry struct {
+	Target          string    `json:"target"`
+	Address         string    `json:"address"`
+	Protocol        string    `json:"protocol"`
+	Subject         string    `json:"subject"`
+	Issuer          string    `json:"issuer"`
+	DNSNames        []string  `json:"dns_names,omitempty"`
+	NotAfter        time.Time `json:"not_after"`
+	DaysUntilExpiry int       `json:"days_until_expiry"`
+	Fingerprint     string    `json:"fingerprint"`
+	Status          string    `json:"status"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// IssuerGroup mengelompokkan sertifikat berdasarkan issuer
+type IssuerGroup struct {
+	Issuer       string               `json:"issuer"`
+	Certificates []*CertificateExpiry `json:"certificates"`
+}
+
+// ExpiryReport menyimpan laporan expiry sertifikat untuk seluruh target
+type ExpiryReport struct {
+	WarnDays     int                  `json:"warn_days"`
+	CritDays     int                  `json:"crit_days"`
+	Status       string               `json:"status"`
+	OK           int                  `json:"ok"`
+	Warning      int                  `json:"warning"`
+	Critical     int                  `json:"critical"`
+	Unknown      int                  `json:"unknown"`
+	Certificates []*CertificateExpiry `json:"certificates"`
+	Issuers      []IssuerGroup        `json:"issuers"`
+	Timestamp    time.Time            `json:"timestamp"`
+}
+
+// NewCertificateExpiry membuat entry expiry dari leaf certificate hasil analisis TLS
+func NewCertificateExpiry(target, address, protocol string, fingerprint *TLSFingerprint) *CertificateExpiry {
+	if fingerprint == nil || fingerprint.Certificate == nil {
+		return nil
+	}
+
+	cert := fingerprint.Certificate
+	return &CertificateExpiry{
+		Target:          target,
+		Address:         address,
+		Protocol:        protocol,
+		Subject:         cert.Subject,
+		Issuer:          cert.Issuer,
+		DNSNames:        cert.DNSNames,
+		NotAfter:        cert.NotAfter,
+		DaysUntilExpiry: cert.DaysUntilExpiry,
+		Fingerprint:     cert.Fingerprint,
+	}
+}
+
+// NewCertificateExpiryError membuat entry UNKNOWN untuk target atau endpoint yang tidak
+// menghasilkan sertifikat (tidak resolve, tidak ada port TLS, handshake gagal)
+func NewCertificateExpiryError(target, address, protocol, reason string) *CertificateExpiry {
+	return &CertificateExpiry{
+		Target:   target,
+		Address:  address,
+		Protocol: protocol,
+		Status:   ExpiryUnknown,
+		Error:    reason,
+	}
+}
+
+// BuildExpiryReport mengurutkan sertifikat berdasarkan sisa hari, menetapkan status
+// per ambang dan mengelompokkan per issuer
+func BuildExpiryReport(entries []*CertificateExpiry, warnDays, critDays int) *ExpiryReport {
+	report := &ExpiryReport{
+		WarnDays:     warnDays,
+		CritDays:     critDays,
+		Status:       ExpiryOK,
+		Certificates: entries,
+		Timestamp:    time.Now(),
+	}
+
+	sort.SliceStable(report.Certificates, func(i, j int) bool {
+		a, b := report.Certificates[i], report.Certificates[j]
+		if (a.Error != "") != (b.Error != "") {
+			// Entry UNKNOWN di awal agar tidak tertutup daftar sertifikat
+			return a.Error != ""
+		}
+		if a.DaysUntilExpiry != b.DaysUntilExpiry {
+			return a.DaysUntilExpiry < b.DaysUntilExpiry
+		}
+		return a.Address < b.Address
+	})
+
+	groups := make(map[string]*IssuerGroup)
+	var issuers []string
+
+	for _, entry := range report.Certificates {
+		if entry.Error != "" {
+			entry.Status = ExpiryUnknown
+			report.Unknown++
+			continue
+		}
+
+		switch {
+		case entry.DaysUntilExpiry <= critDays:
+			entry.Status = ExpiryCritical
+			report.Critical++
+		case entry.DaysUntilExpiry <= warnDays:
+			entry.Status = ExpiryWarning
+			report.Warning++
+		default:
+			entry.Status = ExpiryOK
+			report.OK++
+		}
+
+		group, ok := groups[entry.Issuer]
+		if !ok {
+			group = &IssuerGroup{Issuer: entry.Issuer}
+			groups[entry.Issuer] = group
+			issuers = append(issuers, entry.Issuer)
+		}
+		group.Certificates = append(group.Certificates, entry)
+	}
+
+	// Urutan issuer mengikuti sertifikat paling dekat expiry di grup
+	for _, issuer := range issuers {
+		report.Issuers = append(report.Issuers, *groups[issuer])
+	}
+
+	// Prioritas status: CRITICAL, lalu UNKNOWN, lalu WARNING
+	if report.Critical > 0 {
+		report.Status = ExpiryCritical
+	} else if report.Unknown > 0 {
+		report.Status = ExpiryUnknown
+	} else if report.Warning > 0 {
+		report.Status = ExpiryWarning
+	}
+
+	return report
+}
+
+// ExitCode mengembalikan exit code monitoring: 0 OK, 1 WARNING, 2 CRITICAL, 3 UNKNOWN
+func (r *ExpiryReport) ExitCode() int {
+	switch r.Status {
+	case ExpiryCritical:
+		return 2
+	case ExpiryUnknown:
+		return 3
+	case ExpiryWarning:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+// testExpiry membuat entry expiry dengan sisa hari dan issuer tertentu
+func testExpiry(address, issuer string, days int) *CertificateExpiry {
+	return &CertificateExpiry{Target: "example.test", Address: address, Issuer: issuer, DaysUntilExpiry: days}
+}
+
+func TestBuildExpiryReportThresholds(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{-5, ExpiryCritical},
+		{0, ExpiryCritical},
+		{7, ExpiryCritical},
+		{8, ExpiryWarning},
+		{30, ExpiryWarning},
+		{31, ExpiryOK},
+		{365, ExpiryOK},
+	}
+
+	for _, tt := range tests {
+		report := BuildExpiryReport([]*CertificateExpiry{testExpiry("192.0.2.1:443", "CA", tt.days)}, 30, 7)
+		if got := report.Certificates[0].Status; got != tt.want {
+			t.Errorf("%d hari: status = %s, want %s", tt.days, got, tt.want)
+		}
+		if report.Status != tt.want {
+			t.Errorf("%d hari: report.Status = %s, want %s", tt.days, report.Status, tt.want)
+		}
+	}
+}
+
+func TestBuildExpiryReportStatus(t *testing.T) {
+	unknown := func() *CertificateExpiry {
+		return NewCertificateExpiryError("down.test", "192.0.2.9:443", "tls", "handshake gagal")
+	}
+
+	tests := []struct {
+		name     string
+		entries  []*CertificateExpiry
+		status   string
+		exitCode int
+		counts   [4]int // ok, warning, critical, unknown
+	}{
+		{"kosong", nil, ExpiryOK, 0, [4]int{0, 0, 0, 0}},
+		{"semua ok", []*CertificateExpiry{testExpiry("a:443", "CA", 90), testExpiry("b:443", "CA", 60)},
+			ExpiryOK, 0, [4]int{2, 0, 0, 0}},
+		{"warning", []*CertificateExpiry{testExpiry("a:443", "CA", 90), testExpiry("b:443", "CA", 20)},
+			ExpiryWarning, 1, [4]int{1, 1, 0, 0}},
+		{"unknown mengalahkan warning", []*CertificateExpiry{testExpiry("a:443", "CA", 20), unknown()},
+			ExpiryUnknown, 3, [4]int{0, 1, 0, 1}},
+		{"critical mengalahkan unknown", []*CertificateExpiry{testExpiry("a:443", "CA", 3), unknown(), testExpiry("b:443", "CA", 20)},
+			ExpiryCritical, 2, [4]int{0, 1, 1, 1}},
+		{"hanya unknown", []*CertificateExpiry{unknown()}, ExpiryUnknown, 3, [4]int{0, 0, 0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := BuildExpiryReport(tt.entries, 30, 7)
+			if report.Status != tt.status {
+				t.Errorf("Status = %s, want %s", report.Status, tt.status)
+			}
+			if code := report.ExitCode(); code != tt.exitCode {
+				t.Errorf("ExitCode() = %d, want %d", code, tt.exitCode)
+			}
+			got := [4]int{report.OK, report.Warning, report.Critical, report.Unknown}
+			if got != tt.counts {
+				t.Errorf("ok/warning/critical/unknown = %v, want %v", got, tt.counts)
+			}
+		})
+	}
+}
+
+func TestExpiryReportExitCode(t *testing.T) {
+	tests := []struct {
+		status string
+		want   int
+	}{
+		{ExpiryOK, 0},
+		{ExpiryWarning, 1},
+		{ExpiryCritical, 2},
+		{ExpiryUnknown, 3},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := (&ExpiryReport{Status: tt.status}).ExitCode(); got != tt.want {
+			t.Errorf("ExitCode(%q) = %d, want %d", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestBuildExpiryReportOrdering(t *testing.T) {
+	report := BuildExpiryReport([]*CertificateExpiry{
+		testExpiry("c:443", "CA Satu", 90),
+		testExpiry("b:443", "CA Dua", 10),
+		NewCertificateExpiryError("down.test", "", "", "tidak resolve"),
+		testExpiry("a:443", "CA Satu", 10),
+		testExpiry("d:443", "CA Dua", 2),
+	}, 30, 7)
+
+	var addresses []string
+	for _, cert := range report.Certificates {
+		addresses = append(addresses, cert.Address)
+	}
+	// Entry UNKNOWN di awal, lalu sisa hari naik, lalu alamat
+	if got, want := strings.Join(addresses, ","), ",d:443,a:443,b:443,c:443"; got != want {
+		t.Errorf("urutan sertifikat = %s, want %s", got, want)
+	}
+
+	// Grup issuer mengikuti sertifikat paling dekat expiry dan tidak memuat UNKNOWN
+	if len(report.Issuers) != 2 || report.Issuers[0].Issuer != "CA Dua" || report.Issuers[1].Issuer != "CA Satu" {
+		t.Fatalf("issuers = %+v", report.Issuers)
+	}
+	if len(report.Issuers[0].Certificates) != 2 || len(report.Issuers[1].Certificates) != 2 {
+		t.Errorf("jumlah sertifikat per issuer = %d, %d, want 2, 2",
+			len(report.Issuers[0].Certificates), len(report.Issuers[1].Certificates))
+	}
+}
@@ -0,0 +1,182 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// minRSAKeySize adalah ukuran minimum kunci RSA yang dianggap aman
+const minRSAKeySize = 2048
+
+// tlsAssessment mengumpulkan findings miskonfigurasi TLS untuk satu address
+type tlsAssessment struct {
+	address  string
+	findings []Finding
+}
+
+// AssessTLS menerapkan rule miskonfigurasi TLS ke hasil handshake satu address.
+// Jika hasil enumerasi tersedia, rule versi dan cipher memakai matriks lengkap;
+// jika tidak, versi dinilai dari handshake dan probe versi deprecated, cipher dari
+// yang dinegosiasikan saja
+func AssessTLS(address string, fingerprint *TLSFingerprint, enum *TLSEnumResult) []Finding {
+	a := &tlsAssessment{address: address}
+
+	if fingerprint != nil {
+		a.assessCertificates(fingerprint)
+		a.assessChain(fingerprint)
+	}
+
+	if enum != nil && enum.Error == "" {
+		a.assessEnumeration(enum)
+	} else if fingerprint != nil {
+		a.assessNegotiated(fingerprint)
+	}
+
+	return a.findings
+}
+
+// assessCertificates memeriksa masa berlaku, ukuran kunci dan algoritma signature setiap sertifikat
+func (a *tlsAssessment) assessCertificates(fingerprint *TLSFingerprint) {
+	now := time.Now()
+
+	for i, cert := range fingerprint.Chain {
+		role := "leaf"
+		if i > 0 {
+			role = "intermediate"
+		}
+
+		switch {
+		case now.After(cert.NotAfter):
+			severity := SeverityHigh
+			if i == 0 {
+				severity = SeverityCritical
+			}
+			a.addFinding(severity, fmt.Sprintf("Sertifikat %s expired", role),
+				fmt.Sprintf("%s expired %s", cert.Subject, cert.NotAfter.Format("2006-01-02")))
+		case now.Before(cert.NotBefore):
+			a.addFinding(SeverityHigh, fmt.Sprintf("Sertifikat %s belum berlaku", role),
+				fmt.Sprintf("%s berlaku mulai %s", cert.Subject, cert.NotBefore.Format("2006-01-02")))
+		}
+
+		if cert.KeyAlgorithm == "RSA" && cert.KeySize > 0 && cert.KeySize < minRSAKeySize {
+			a.addFinding(SeverityHigh, "Kunci RSA lemah",
+				fmt.Sprintf("%s memakai RSA %d bit (minimal %d)", cert.Subject, cert.KeySize, minRSAKeySize))
+		}
+
+		// Signature root self-signed tidak diverifikasi client, jadi SHA-1 di sana tidak relevan
+		if i > 0 && cert.SelfSigned {
+			continue
+		}
+		if strings.Contains(strings.ToUpper(cert.SignatureAlgorithm), "SHA1") {
+			a.addFinding(SeverityMedium, "Signature SHA-1",
+				fmt.Sprintf("%s ditandatangani dengan %s", cert.Subject, cert.SignatureAlgorithm))
+		}
+	}
+}
+
+// assessChain memeriksa hostname, trust chain dan intermediate yang hilang
+func (a *tlsAssessment) assessChain(fingerprint *TLSFingerprint) {
+	validation := fingerprint.Validation
+	leaf := fingerprint.Certificate
+	if validation == nil || leaf == nil {
+		return
+	}
+
+	if !validation.HostnameMatch {
+		a.addFinding(SeverityHigh, "Hostname tidak cocok dengan sertifikat",
+			fmt.Sprintf("%s tidak ada di SAN %s", fingerprint.ServerName, strings.Join(leaf.DNSNames, ", ")))
+	}
+
+	if validation.Valid {
+		return
+	}
+
+	switch validation.Reason {
+	case "self_signed":
+		a.addFinding(SeverityHigh, "Sertifikat self-signed", leaf.Subject)
+	case "unknown_authority":
+		if len(fingerprint.Chain) == 1 && !leaf.SelfSigned {
+			// Server hanya mengirim leaf, client tanpa AIA fetching gagal membangun chain
+			a.addFinding(SeverityMedium, "Intermediate certificate tidak dikirim",
+				fmt.Sprintf("issuer %s tidak ada di chain", leaf.Issuer))
+		} else {
+			a.addFinding(SeverityHigh, "Chain tidak dipercaya",
+				fmt.Sprintf("root untuk %s tidak ada di %s", leaf.Issuer, validation.Roots))
+		}
+	case "expired", "not_yet_valid", "hostname_mismatch":
+		// Sudah dilaporkan oleh rule masa berlaku dan hostname
+	default:
+		a.addFinding(SeverityHigh, "Chain tidak valid", fmt.Sprintf("%s: %s", validation.Reason, validation.Error))
+	}
+}
+
+// assessEnumeration memeriksa versi lama dan cipher CBC/RSA key exchange dari hasil enumerasi
+func (a *tlsAssessment) assessEnumeration(enum *TLSEnumResult) {
+	for _, protocol := range enum.Protocols {
+		if !protocol.Supported {
+			continue
+		}
+
+		a.assessVersion(protocol.Version)
+
+		var cbc, rsaKex []string
+		for _, cipher := range protocol.Ciphers {
+			for _, issue := range cipher.Issues {
+				switch issue {
+				case "cbc":
+					cbc = append(cbc, cipher.Name)
+				case "rsa_kex":
+					rsaKex = append(rsaKex, cipher.Name)
+				}
+			}
+		}
+
+		if len(rsaKex) > 0 {
+			a.addFinding(SeverityMedium, fmt.Sprintf("RSA key exchange di %s", protocol.Version),
+				"tanpa forward secrecy: "+strings.Join(rsaKex, ", "))
+		}
+		if len(cbc) > 0 {
+			a.addFinding(SeverityLow, fmt.Sprintf("Cipher CBC di %s", protocol.Version), strings.Join(cbc, ", "))
+		}
+	}
+}
+
+// assessNegotiated memeriksa versi dan cipher yang dinegosiasikan serta hasil probe
+// versi deprecated saat enumerasi tidak dijalankan
+func (a *tlsAssessment) assessNegotiated(fingerprint *TLSFingerprint) {
+	a.assessVersion(fingerprint.TLSVersion)
+	for _, version := range fingerprint.LegacyVersions {
+		if version != fingerprint.TLSVersion {
+			a.assessVersion(version)
+		}
+	}
+
+	if fingerprint.KeyExchange == "RSA" {
+		a.addFinding(SeverityMedium, "RSA key exchange dinegosiasikan",
+			"tanpa forward secrecy: "+fingerprint.CipherSuite)
+	}
+	if strings.Contains(fingerprint.CipherSuite, "_CBC_") {
+		a.addFinding(SeverityLow, "Cipher CBC dinegosiasikan", fingerprint.CipherSuite)
+	}
+}
+
+// assessVersion melaporkan protokol deprecated
+func (a *tlsAssessment) assessVersion(version string) {
+	switch version {
+	case "SSL 3.0":
+		a.addFinding(SeverityHigh, "SSLv3 didukung", "rentan POODLE, nonaktifkan SSLv3")
+	case "TLS 1.0", "TLS 1.1":
+		a.addFinding(SeverityMedium, version+" didukung", "protokol deprecated (RFC 8996)")
+	}
+}
+
+// addFinding menambahkan finding TLS dengan address sebagai awalan detail
+func (a *tlsAssessment) addFinding(severity, title, detail string) {
+	if detail != "" {
+		detail = a.address + ": " + detail
+	} else {
+		detail = a.address
+	}
+	a.findings = append(a.findings, NewFinding(severity, "tls", title, detail))
+}
@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// healthyTLSFingerprint membuat hasil handshake tanpa miskonfigurasi
+func healthyTLSFingerprint() *TLSFingerprint {
+	now := time.Now()
+	leaf := &CertificateInfo{
+		Subject:            "CN=example.test",
+		Issuer:             "CN=Test Intermediate",
+		NotBefore:          now.Add(-24 * time.Hour),
+		NotAfter:           now.Add(90 * 24 * time.Hour),
+		DNSNames:           []string{"example.test"},
+		KeyAlgorithm:       "RSA",
+		KeySize:            2048,
+		SignatureAlgorithm: "SHA256-RSA",
+	}
+	intermediate := &CertificateInfo{
+		Subject:            "CN=Test Intermediate",
+		Issuer:             "CN=Test Root",
+		NotBefore:          now.Add(-365 * 24 * time.Hour),
+		NotAfter:           now.Add(365 * 24 * time.Hour),
+		KeyAlgorithm:       "ECDSA",
+		KeySize:            256,
+		SignatureAlgorithm: "SHA384-RSA",
+		IsCA:               true,
+	}
+
+	return &TLSFingerprint{
+		TLSVersion:  "TLS 1.3",
+		CipherSuite: "TLS_AES_128_GCM_SHA256",
+		ServerName:  "example.test",
+		Certificate: leaf,
+		Chain:       []*CertificateInfo{leaf, intermediate},
+		Validation:  &ChainValidation{Valid: true, Roots: "system", HostnameMatch: true},
+		KeyExchange: "ECDHE",
+	}
+}
+
+// findingSummary memformat findings menjadi "severity:title" untuk dibandingkan
+func findingSummary(findings []Finding) []string {
+	summary := []string{}
+	for _, finding := range findings {
+		summary = append(summary, finding.Severity+":"+finding.Title)
+	}
+	return summary
+}
+
+func TestAssessTLSRules(t *testing.T) {
+	past := time.Now().Add(-48 * time.Hour)
+	future := time.Now().Add(48 * time.Hour)
+
+	tests := []struct {
+		name   string
+		modify func(fp *TLSFingerprint)
+		want   []string
+	}{
+		{"konfigurasi sehat", func(fp *TLSFingerprint) {}, []string{}},
+		{"leaf expired", func(fp *TLSFingerprint) {
+			fp.Chain[0].NotAfter = past
+			fp.Validation = &ChainValidation{Reason: "expired", HostnameMatch: true}
+		}, []string{"critical:Sertifikat leaf expired"}},
+		{"intermediate expired", func(fp *TLSFingerprint) {
+			fp.Chain[1].NotAfter = past
+		}, []string{"high:Sertifikat intermediate expired"}},
+		{"leaf belum berlaku", func(fp *TLSFingerprint) {
+			fp.Chain[0].NotBefore = future
+			fp.Validation = &ChainValidation{Reason: "not_yet_valid", HostnameMatch: true}
+		}, []string{"high:Sertifikat leaf belum berlaku"}},
+		{"kunci RSA lemah", func(fp *TLSFingerprint) {
+			fp.Chain[0].KeySize = 1024
+		}, []string{"high:Kunci RSA lemah"}},
+		{"ECDSA 256 bukan kunci lemah", func(fp *TLSFingerprint) {
+			fp.Chain[0].KeyAlgorithm = "ECDSA"
+			fp.Chain[0].KeySize = 256
+		}, []string{}},
+		{"signature SHA-1 di leaf", func(fp *TLSFingerprint) {
+			fp.Chain[0].SignatureAlgorithm = "SHA1-RSA"
+		}, []string{"medium:Signature SHA-1"}},
+		{"SHA-1 di root self-signed diabaikan", func(fp *TLSFingerprint) {
+			fp.Chain = append(fp.Chain, &CertificateInfo{
+				Subject:            "CN=Test Root",
+				NotBefore:          past,
+				NotAfter:           future,
+				SignatureAlgorithm: "SHA1-RSA",
+				SelfSigned:         true,
+			})
+		}, []string{}},
+		{"hostname tidak cocok", func(fp *TLSFingerprint) {
+			fp.ServerName = "other.test"
+			fp.Validation = &ChainValidation{Reason: "hostname_mismatch"}
+		}, []string{"high:Hostname tidak cocok dengan sertifikat"}},
+		{"self-signed", func(fp *TLSFingerprint) {
+			fp.Chain = fp.Chain[:1]
+			fp.Chain[0].SelfSigned = true
+			fp.Validation = &ChainValidation{Reason: "self_signed", HostnameMatch: true}
+		}, []string{"high:Sertifikat self-signed"}},
+		{"intermediate tidak dikirim", func(fp *TLSFingerprint) {
+			fp.Chain = fp.Chain[:1]
+			fp.Validation = &ChainValidation{Reason: "unknown_authority", HostnameMatch: true}
+		}, []string{"medium:Intermediate certificate tidak dikirim"}},
+		{"chain tidak dipercaya", func(fp *TLSFingerprint) {
+			fp.Validation = &ChainValidation{Reason: "unknown_authority", Roots: "ca-bundle", HostnameMatch: true}
+		}, []string{"high:Chain tidak dipercaya"}},
+		{"chain tidak valid", func(fp *TLSFingerprint) {
+			fp.Validation = &ChainValidation{Reason: "invalid", Error: "bad constraint", HostnameMatch: true}
+		}, []string{"high:Chain tidak valid"}},
+		{"TLS 1.0 dinegosiasikan", func(fp *TLSFingerprint) {
+			fp.TLSVersion = "TLS 1.0"
+		}, []string{"medium:TLS 1.0 didukung"}},
+		{"versi deprecated dari probe legacy", func(fp *TLSFingerprint) {
+			fp.LegacyVersions = []string{"SSL 3.0", "TLS 1.1"}
+		}, []string{"high:SSLv3 didukung", "medium:TLS 1.1 didukung"}},
+		{"versi legacy sama dengan handshake tidak dobel", func(fp *TLSFingerprint) {
+			fp.TLSVersion = "TLS 1.1"
+			fp.LegacyVersions = []string{"TLS 1.1"}
+		}, []string{"medium:TLS 1.1 didukung"}},
+		{"RSA key exchange dan CBC dinegosiasikan", func(fp *TLSFingerprint) {
+			fp.TLSVersion = "TLS 1.2"
+			fp.CipherSuite = "TLS_RSA_WITH_AES_128_CBC_SHA"
+			fp.KeyExchange = "RSA"
+		}, []string{"medium:RSA key exchange dinegosiasikan", "low:Cipher CBC dinegosiasikan"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fingerprint := healthyTLSFingerprint()
+			tt.modify(fingerprint)
+
+			got := findingSummary(AssessTLS("192.0.2.1:443", fingerprint, nil))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssessTLSEnumeration(t *testing.T) {
+	enum := &TLSEnumResult{Protocols: []TLSProtocolSupport{
+		{Version: "SSL 3.0"},
+		{Version: "TLS 1.0", Supported: true, Ciphers: []CipherSuiteSupport{
+			{Name: "TLS_RSA_WITH_AES_128_CBC_SHA", Issues: []string{"cbc", "rsa_kex"}},
+			{Name: "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA", Issues: []string{"cbc"}},
+		}},
+		{Version: "TLS 1.2", Supported: true, Ciphers: []CipherSuiteSupport{
+			{Name: "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		}},
+	}}
+
+	// Enumerasi menggantikan rule versi/cipher yang dinegosiasikan
+	fingerprint := healthyTLSFingerprint()
+	fingerprint.KeyExchange = "RSA"
+
+	tests := []struct {
+		name string
+		enum *TLSEnumResult
+		want []string
+	}{
+		{"matriks enumerasi", enum, []string{
+			"medium:TLS 1.0 didukung",
+			"medium:RSA key exchange di TLS 1.0",
+			"low:Cipher CBC di TLS 1.0",
+		}},
+		{"enumerasi gagal memakai handshake", &TLSEnumResult{Error: "timeout"}, []string{
+			"medium:RSA key exchange dinegosiasikan",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findingSummary(AssessTLS("192.0.2.1:443", fingerprint, tt.enum))
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssessTLSDetailPrefix(t *testing.T) {
+	fingerprint := healthyTLSFingerprint()
+	fingerprint.TLSVersion = "TLS 1.0"
+
+	findings := AssessTLS("192.0.2.1:443", fingerprint, nil)
+	if len(findings) != 1 {
+		t.Fatalf("findings = %v", findings)
+	}
+	if findings[0].Category != "tls" || findings[0].Detail != "192.0.2.1:443: protokol deprecated (RFC 8996)" {
+		t.Errorf("finding = %+v", findings[0])
+	}
+
+	if findings := AssessTLS("192.0.2.1:443", nil, nil); len(findings) != 0 {
+		t.Errorf("AssessTLS tanpa fingerprint = %v, want kosong", findings)
+	}
+}
+
+func TestHighestSeverity(t *testing.T) {
+	finding := func(severity string) Finding {
+		return NewFinding(severity, "tls", "test", "")
+	}
+
+	tests := []struct {
+		findings []Finding
+		want     string
+	}{
+		{nil, ""},
+		{[]Finding{finding(SeverityInfo)}, SeverityInfo},
+		{[]Finding{finding(SeverityLow), finding(SeverityInfo)}, SeverityLow},
+		{[]Finding{finding(SeverityMedium), finding(SeverityHigh), finding(SeverityLow)}, SeverityHigh},
+		{[]Finding{finding(SeverityHigh), finding(SeverityCritical), finding(SeverityMedium)}, SeverityCritical},
+		{[]Finding{finding("bogus")}, ""},
+		{[]Finding{finding("bogus"), finding(SeverityLow)}, SeverityLow},
+	}
+
+	for _, tt := range tests {
+		if got := HighestSeverity(tt.findings); got != tt.want {
+			t.Errorf("HighestSeverity(%v) = %q, want %q", findingSummary(tt.findings), got, tt.want)
+		}
+	}
+}
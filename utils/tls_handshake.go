@@ -0,0 +1,311 @@
+package utils
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// maxRecordedHandshake membatasi jumlah byte yang direkam per arah koneksi
+const maxRecordedHandshake = 64 * 1024
+
+// TLS record dan handshake type yang dipakai parser
+const (
+	tlsRecordHandshake       = 22
+	tlsRecordApplicationData = 23
+	tlsHandshakeClientHello  = 1
+	tlsHandshakeServerHello  = 2
+)
+
+// TLS extension type yang di-parse untuk fingerprint
+const (
+	tlsExtSupportedGroups = 10
+	tlsExtPointFormats    = 11
+)
+
+// recordingConn membungkus net.Conn dan merekam byte yang dikirim dan diterima
+// sehingga ClientHello/ServerHello asli dapat di-parse setelah handshake
+type recordingConn struct {
+	net.Conn
+	mutex   sync.Mutex
+	written []byte
+	read    []byte
+}
+
+// newRecordingConn membuat recordingConn baru
+func newRecordingConn(conn net.Conn) *recordingConn {
+	return &recordingConn{Conn: conn}
+}
+
+// Read membaca dari koneksi dan merekam byte dari server
+func (c *recordingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.record(&c.read, b[:n])
+	return n, err
+}
+
+// Write menulis ke koneksi dan merekam byte dari client
+func (c *recordingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.record(&c.written, b[:n])
+	return n, err
+}
+
+// record menambahkan data ke buffer sampai batas maxRecordedHandshake
+func (c *recordingConn) record(buf *[]byte, data []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if remaining := maxRecordedHandshake - len(*buf); remaining > 0 {
+		if len(data) > remaining {
+			data = data[:remaining]
+		}
+		*buf = append(*buf, data...)
+	}
+}
+
+// ClientBytes mengembalikan salinan byte yang dikirim client
+func (c *recordingConn) ClientBytes() []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]byte{}, c.written...)
+}
+
+// ServerBytes mengembalikan salinan byte yang diterima dari server
+func (c *recordingConn) ServerBytes() []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]byte{}, c.read...)
+}
+
+// helloMessage menyimpan field ClientHello/ServerHello yang relevan untuk fingerprint
+type helloMessage struct {
+	Version      uint16
+	CipherSuites []uint16
+	Extensions   []uint16
+	Curves       []uint16
+	PointFormats []uint8
+	ExtData      map[uint16][]byte
+}
+
+// extractHandshake menggabungkan record handshake dari stream dan mengembalikan
+// body handshake message pertama dengan tipe msgType
+func extractHandshake(stream []byte, msgType uint8) ([]byte, error) {
+	var handshake []byte
+
+	for len(stream) >= 5 {
+		recordType := stream[0]
+		length := int(stream[3])<<8 | int(stream[4])
+		if len(stream) < 5+length {
+			// Record terpotong, pakai bagian yang sudah ada
+			length = len(stream) - 5
+		}
+
+		// Record terenkripsi (TLS 1.3) berarti handshake plaintext sudah selesai
+		if recordType == tlsRecordApplicationData {
+			break
+		}
+		if recordType == tlsRecordHandshake {
+			handshake = append(handshake, stream[5:5+length]...)
+		}
+		stream = stream[5+length:]
+	}
+
+	for len(handshake) >= 4 {
+		length := int(handshake[1])<<16 | int(handshake[2])<<8 | int(handshake[3])
+		if len(handshake) < 4+length {
+			return nil, fmt.Errorf("handshake message %d terpotong", handshake[0])
+		}
+		if handshake[0] == msgType {
+			return handshake[4 : 4+length], nil
+		}
+		handshake = handshake[4+length:]
+	}
+
+	return nil, fmt.Errorf("handshake message %d tidak ditemukan", msgType)
+}
+
+// parseClientHello mem-parse body ClientHello
+func parseClientHello(body []byte) (*helloMessage, error) {
+	r := &byteReader{data: body}
+	hello := &helloMessage{ExtData: make(map[uint16][]byte)}
+
+	hello.Version = r.uint16()
+	r.skip(32) // random
+	r.skip(int(r.uint8()))
+
+	ciphers := &byteReader{data: r.bytes(int(r.uint16()))}
+	for ciphers.remaining() >= 2 {
+		hello.CipherSuites = append(hello.CipherSuites, ciphers.uint16())
+	}
+
+	r.skip(int(r.uint8())) // compression methods
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if err := hello.parseExtensions(r); err != nil {
+		return nil, err
+	}
+
+	return hello, nil
+}
+
+// parseServerHello mem-parse body ServerHello
+func parseServerHello(body []byte) (*helloMessage, error) {
+	r := &byteReader{data: body}
+	hello := &helloMessage{ExtData: make(map[uint16][]byte)}
+
+	hello.Version = r.uint16()
+	r.skip(32) // random
+	r.skip(int(r.uint8()))
+	hello.CipherSuites = []uint16{r.uint16()}
+	r.skip(1) // compression method
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if err := hello.parseExtensions(r); err != nil {
+		return nil, err
+	}
+
+	return hello, nil
+}
+
+// parseExtensions mem-parse daftar extension (opsional pada hello lama)
+func (h *helloMessage) parseExtensions(r *byteReader) error {
+	if r.remaining() == 0 {
+		return nil
+	}
+
+	extensions := &byteReader{data: r.bytes(int(r.uint16()))}
+	for extensions.remaining() >= 4 {
+		extType := extensions.uint16()
+		data := extensions.bytes(int(extensions.uint16()))
+
+		h.Extensions = append(h.Extensions, extType)
+		h.ExtData[extType] = data
+
+		switch extType {
+		case tlsExtSupportedGroups:
+			groups := &byteReader{data: data}
+			groups = &byteReader{data: groups.bytes(int(groups.uint16()))}
+			for groups.remaining() >= 2 {
+				h.Curves = append(h.Curves, groups.uint16())
+			}
+		case tlsExtPointFormats:
+			formats := &byteReader{data: data}
+			h.PointFormats = append(h.PointFormats, formats.bytes(int(formats.uint8()))...)
+		}
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+	return extensions.err
+}
+
+// ja3String menyusun string JA3: Version,Ciphers,Extensions,Curves,PointFormats
+func (h *helloMessage) ja3String() string {
+	return strings.Join([]string{
+		fmt.Sprint(h.Version),
+		joinUint16(h.CipherSuites),
+		joinUint16(h.Extensions),
+		joinUint16(h.Curves),
+		joinUint8(h.PointFormats),
+	}, ",")
+}
+
+// ja3sString menyusun string JA3S: Version,Cipher,Extensions
+func (h *helloMessage) ja3sString() string {
+	return strings.Join([]string{
+		fmt.Sprint(h.Version),
+		joinUint16(h.CipherSuites),
+		joinUint16(h.Extensions),
+	}, ",")
+}
+
+// ja3Hash menghasilkan MD5 hex dari string JA3/JA3S
+func ja3Hash(value string) string {
+	sum := md5.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// isGREASE mengecek nilai GREASE (RFC 8701) yang dibuang dari JA3
+func isGREASE(value uint16) bool {
+	return value&0x0f0f == 0x0a0a && value>>8 == value&0xff
+}
+
+// joinUint16 menggabungkan nilai dengan "-" tanpa GREASE
+func joinUint16(values []uint16) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if !isGREASE(value) {
+			parts = append(parts, fmt.Sprint(value))
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// joinUint8 menggabungkan nilai dengan "-"
+func joinUint8(values []uint8) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, fmt.Sprint(value))
+	}
+	return strings.Join(parts, "-")
+}
+
+// errShortBuffer dikembalikan byteReader saat data habis
+var errShortBuffer = errors.New("data handshake terpotong")
+
+// byteReader membaca field big-endian dengan pengecekan batas
+type byteReader struct {
+	data []byte
+	err  error
+}
+
+// remaining mengembalikan jumlah byte yang belum dibaca
+func (r *byteReader) remaining() int {
+	return len(r.data)
+}
+
+// bytes membaca n byte
+func (r *byteReader) bytes(n int) []byte {
+	if r.err != nil || n > len(r.data) {
+		r.err = errShortBuffer
+		return nil
+	}
+	out := r.data[:n]
+	r.data = r.data[n:]
+	return out
+}
+
+// skip melewati n byte
+func (r *byteReader) skip(n int) {
+	r.bytes(n)
+}
+
+// uint8 membaca satu byte
+func (r *byteReader) uint8() uint8 {
+	b := r.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// uint16 membaca dua byte big-endian
+func (r *byteReader) uint16() uint16 {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return uint16(b[0])<<8 | uint16(b[1])
+}
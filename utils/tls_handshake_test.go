@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testExtension adalah extension mentah untuk menyusun ClientHello di test
+type testExtension struct {
+	extType uint16
+	data    []byte
+}
+
+// buildTestClientHello menyusun body ClientHello dengan session ID kosong
+func buildTestClientHello(version uint16, ciphers []uint16, extensions []testExtension) []byte {
+	body := []byte{byte(version >> 8), byte(version)}
+	body = append(body, make([]byte, 32)...) // random
+	body = append(body, 0)                   // session ID
+
+	body = append(body, byte(len(ciphers)*2>>8), byte(len(ciphers)*2))
+	for _, cipher := range ciphers {
+		body = append(body, byte(cipher>>8), byte(cipher))
+	}
+	body = append(body, 1, 0) // compression: null
+
+	if extensions == nil {
+		return body
+	}
+
+	var exts []byte
+	for _, ext := range extensions {
+		exts = append(exts, byte(ext.extType>>8), byte(ext.extType), byte(len(ext.data)>>8), byte(len(ext.data)))
+		exts = append(exts, ext.data...)
+	}
+	body = append(body, byte(len(exts)>>8), byte(len(exts)))
+	return append(body, exts...)
+}
+
+// testHandshakeMessage membungkus body dengan header handshake
+func testHandshakeMessage(msgType uint8, body []byte) []byte {
+	return append([]byte{msgType, byte(len(body) >> 16), byte(len(body) >> 8), byte(len(body))}, body...)
+}
+
+// testRecord membungkus payload dengan header TLS record
+func testRecord(recordType uint8, payload []byte) []byte {
+	return append([]byte{recordType, 3, 1, byte(len(payload) >> 8), byte(len(payload))}, payload...)
+}
+
+func TestExtractHandshake(t *testing.T) {
+	clientHello := testHandshakeMessage(tlsHandshakeClientHello, []byte("client"))
+	serverHello := testHandshakeMessage(tlsHandshakeServerHello, []byte("server"))
+	both := append(append([]byte{}, serverHello...), testHandshakeMessage(11, []byte("cert"))...)
+
+	tests := []struct {
+		name    string
+		stream  []byte
+		msgType uint8
+		want    string
+		wantErr string
+	}{
+		{"satu record", testRecord(tlsRecordHandshake, clientHello), tlsHandshakeClientHello, "client", ""},
+		{"message kedua dalam record", testRecord(tlsRecordHandshake, both), 11, "cert", ""},
+		{"message terbagi dua record",
+			append(testRecord(tlsRecordHandshake, serverHello[:5]), testRecord(tlsRecordHandshake, serverHello[5:])...),
+			tlsHandshakeServerHello, "server", ""},
+		{"record non-handshake dilewati",
+			append(testRecord(20, []byte{1}), testRecord(tlsRecordHandshake, serverHello)...),
+			tlsHandshakeServerHello, "server", ""},
+		{"berhenti di application data",
+			append(testRecord(tlsRecordApplicationData, []byte{1, 2, 3}), testRecord(tlsRecordHandshake, serverHello)...),
+			tlsHandshakeServerHello, "", "tidak ditemukan"},
+		{"record terpotong", testRecord(tlsRecordHandshake, serverHello)[:12], tlsHandshakeServerHello, "", "terpotong"},
+		{"tipe tidak ada", testRecord(tlsRecordHandshake, clientHello), tlsHandshakeServerHello, "", "tidak ditemukan"},
+		{"stream kosong", nil, tlsHandshakeClientHello, "", "tidak ditemukan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := extractHandshake(tt.stream, tt.msgType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(body) != tt.want {
+				t.Errorf("body = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsGREASE(t *testing.T) {
+	tests := []struct {
+		value uint16
+		want  bool
+	}{
+		{0x0a0a, true},
+		{0x1a1a, true},
+		{0x7a7a, true},
+		{0xfafa, true},
+		{0x0a1a, false},
+		{0x1a0a, false},
+		{0x0b0b, false},
+		{0x000a, false},
+		{0x1301, false},
+		{0xc02f, false},
+	}
+
+	for _, tt := range tests {
+		if got := isGREASE(tt.value); got != tt.want {
+			t.Errorf("isGREASE(%#04x) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseClientHello(t *testing.T) {
+	groups := []byte{0, 6, 0x3a, 0x3a, 0, 29, 0, 23}
+	formats := []byte{1, 0}
+
+	tests := []struct {
+		name         string
+		body         []byte
+		ja3          string
+		curves       []uint16
+		pointFormats []uint8
+		wantErr      bool
+	}{
+		{
+			name: "dengan GREASE",
+			body: buildTestClientHello(0x0303, []uint16{0x2a2a, 0x1301, 0xc02f}, []testExtension{
+				{0x0a0a, nil},
+				{0, []byte{0, 0}},
+				{tlsExtSupportedGroups, groups},
+				{tlsExtPointFormats, formats},
+				{0xfafa, []byte{0}},
+			}),
+			ja3:          "771,4865-49199,0-10-11,29-23,0",
+			curves:       []uint16{0x3a3a, 29, 23},
+			pointFormats: []uint8{0},
+		},
+		{
+			name: "tanpa extension",
+			body: buildTestClientHello(0x0301, []uint16{0x002f, 0x0035}, nil),
+			ja3:  "769,47-53,,,",
+		},
+		{
+			name:    "body terpotong",
+			body:    buildTestClientHello(0x0303, []uint16{0x1301}, nil)[:36],
+			wantErr: true,
+		},
+		{
+			name: "extension terpotong",
+			body: func() []byte {
+				body := buildTestClientHello(0x0303, []uint16{0x1301}, []testExtension{{tlsExtSupportedGroups, groups}})
+				return body[:len(body)-2]
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hello, err := parseClientHello(tt.body)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseClientHello berhasil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := hello.ja3String(); got != tt.ja3 {
+				t.Errorf("ja3String() = %q, want %q", got, tt.ja3)
+			}
+			if joinUint16(hello.Curves) != joinUint16(tt.curves) || joinUint8(hello.PointFormats) != joinUint8(tt.pointFormats) {
+				t.Errorf("curves = %v formats = %v, want %v %v", hello.Curves, hello.PointFormats, tt.curves, tt.pointFormats)
+			}
+		})
+	}
+}
+
+func TestJA3Hash(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", "d41d8cd98f00b204e9800998ecf8427e"},
+		// Contoh dari dokumentasi JA3 (salesforce/ja3)
+		{"769,47-53-5-10-49161-49162-49171-49172-50-56-19-4,0-10-11,23-24-25,0", "ada70206e40642a3e4461f35503241d5"},
+	}
+
+	for _, tt := range tests {
+		if got := ja3Hash(tt.value); got != tt.want {
+			t.Errorf("ja3Hash(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRecordedClientHelloJA3(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	// Server hanya membaca ClientHello lalu menutup koneksi
+	go func() {
+		buf := make([]byte, 4096)
+		server.SetReadDeadline(time.Now().Add(2 * time.Second))
+		server.Read(buf)
+		server.Close()
+	}()
+
+	recorder := newRecordingConn(client)
+	conn := tls.Client(recorder, &tls.Config{
+		ServerName:   "example.test",
+		MinVersion:   tls.VersionTLS12,
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	})
+	conn.Handshake()
+	conn.Close()
+
+	body, err := extractHandshake(recorder.ClientBytes(), tlsHandshakeClientHello)
+	if err != nil {
+		t.Fatalf("extractHandshake: %v", err)
+	}
+	hello, err := parseClientHello(body)
+	if err != nil {
+		t.Fatalf("parseClientHello: %v", err)
+	}
+
+	fields := strings.Split(hello.ja3String(), ",")
+	if len(fields) != 5 {
+		t.Fatalf("ja3String() = %q, want 5 field", hello.ja3String())
+	}
+	if fields[0] != "771" {
+		t.Errorf("version = %s, want 771", fields[0])
+	}
+	// Urutan cipher ditentukan crypto/tls, cukup cek isinya
+	if ciphers := strings.Split(fields[1], "-"); len(ciphers) != 2 || !strings.Contains(fields[1], "49195") || !strings.Contains(fields[1], "49199") {
+		t.Errorf("ciphers = %s, want 49195 dan 49199", fields[1])
+	}
+	if !strings.HasPrefix(fields[2], "0-") {
+		t.Errorf("extensions = %s, want diawali server_name (0)", fields[2])
+	}
+	if fields[3] == "" || fields[4] != "0" {
+		t.Errorf("curves = %q formats = %q", fields[3], fields[4])
+	}
+}
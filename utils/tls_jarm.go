@@ -0,0 +1,437 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Urutan cipher, ALPN dan supported_versions pada probe JARM
+const (
+	jarmForward    = "FORWARD"
+	jarmReverse    = "REVERSE"
+	jarmTopHalf    = "TOP_HALF"
+	jarmBottomHalf = "BOTTOM_HALF"
+	jarmMiddleOut  = "MIDDLE_OUT"
+)
+
+// Extension tambahan yang dikirim probe JARM
+const (
+	tlsExtMaxFragmentLength  = 1
+	tlsExtALPN               = 16
+	tlsExtExtendedMasterSec  = 23
+	tlsExtSessionTicket      = 35
+	tlsExtPSKKeyExchangeMode = 45
+	tlsExtRenegotiationInfo  = 0xff01
+)
+
+// jarmEmptyProbe adalah hasil probe tanpa ServerHello
+const jarmEmptyProbe = "|||"
+
+// jarmCiphers adalah daftar cipher "ALL" JARM dalam urutan FORWARD
+var jarmCiphers = []uint16{
+	0x0016, 0x0033, 0x0067, 0xc09e, 0xc0a2, 0x009e, 0x0039, 0x006b, 0xc09f, 0xc0a3,
+	0x009f, 0x0045, 0x00be, 0x0088, 0x00c4, 0x009a, 0xc008, 0xc009, 0xc023, 0xc0ac,
+	0xc0ae, 0xc02b, 0xc00a, 0xc024, 0xc0ad, 0xc0af, 0xc02c, 0xc072, 0xc073, 0xcca9,
+	0x1302, 0x1301, 0xcc14, 0xc007, 0xc012, 0xc013, 0xc027, 0xc02f, 0xc014, 0xc028,
+	0xc030, 0xc060, 0xc061, 0xc076, 0xc077, 0xcca8, 0x1305, 0x1304, 0x1303, 0xcc13,
+	0xc011, 0x000a, 0x002f, 0x003c, 0xc09c, 0xc0a0, 0x009c, 0x0035, 0x003d, 0xc09d,
+	0xc0a1, 0x009d, 0x0041, 0x00ba, 0x0084, 0x00c0, 0x0007, 0x0004, 0x0005,
+}
+
+// jarmCipherIndex adalah urutan cipher untuk byte cipher di hash JARM
+var jarmCipherIndex = []uint16{
+	0x0004, 0x0005, 0x0007, 0x000a, 0x0016, 0x002f, 0x0033, 0x0035, 0x0039, 0x003c,
+	0x003d, 0x0041, 0x0045, 0x0067, 0x006b, 0x0084, 0x0088, 0x009a, 0x009c, 0x009d,
+	0x009e, 0x009f, 0x00ba, 0x00be, 0x00c0, 0x00c4, 0xc007, 0xc008, 0xc009, 0xc00a,
+	0xc011, 0xc012, 0xc013, 0xc014, 0xc023, 0xc024, 0xc027, 0xc028, 0xc02b, 0xc02c,
+	0xc02f, 0xc030, 0xc060, 0xc061, 0xc072, 0xc073, 0xc076, 0xc077, 0xc09c, 0xc09d,
+	0xc09e, 0xc09f, 0xc0a0, 0xc0a1, 0xc0a2, 0xc0a3, 0xc0ac, 0xc0ad, 0xc0ae, 0xc0af,
+	0xcc13, 0xcc14, 0xcca8, 0xcca9, 0x1301, 0x1302, 0x1303, 0x1304, 0x1305,
+}
+
+// jarmALPN dan jarmRareALPN adalah daftar ALPN probe JARM (lemah ke kuat)
+var (
+	jarmALPN     = []string{"http/0.9", "http/1.0", "http/1.1", "spdy/1", "spdy/2", "spdy/3", "h2", "h2c", "hq"}
+	jarmRareALPN = []string{"http/0.9", "http/1.0", "spdy/1", "spdy/2", "spdy/3", "h2c", "hq"}
+)
+
+// jarmProbe mendeskripsikan satu ClientHello JARM
+type jarmProbe struct {
+	Name           string
+	RecordVersion  uint16
+	HelloVersion   uint16
+	TLS13          bool
+	NoTLS13Ciphers bool
+	CipherOrder    string
+	GREASE         bool
+	RareALPN       bool
+	// SupportedVersions: "1.2" (tanpa TLS 1.3), "1.3", atau "" (tanpa extension)
+	SupportedVersions string
+	ExtensionOrder    string
+}
+
+// jarmProbes adalah 10 ClientHello JARM dalam urutan yang menentukan fingerprint
+var jarmProbes = []jarmProbe{
+	{"tls1_2_forward", versionTLS12, versionTLS12, false, false, jarmForward, false, false, "1.2", jarmReverse},
+	{"tls1_2_reverse", versionTLS12, versionTLS12, false, false, jarmReverse, false, false, "1.2", jarmForward},
+	{"tls1_2_top_half", versionTLS12, versionTLS12, false, false, jarmTopHalf, false, false, "", jarmForward},
+	{"tls1_2_bottom_half", versionTLS12, versionTLS12, false, false, jarmBottomHalf, false, true, "", jarmForward},
+	{"tls1_2_middle_out", versionTLS12, versionTLS12, false, false, jarmMiddleOut, true, true, "", jarmReverse},
+	{"tls1_1_middle_out", versionTLS11, versionTLS11, false, false, jarmForward, false, false, "", jarmForward},
+	{"tls1_3_forward", versionTLS10, versionTLS12, true, false, jarmForward, false, false, "1.3", jarmReverse},
+	{"tls1_3_reverse", versionTLS10, versionTLS12, true, false, jarmReverse, false, false, "1.3", jarmForward},
+	{"tls1_3_invalid", versionTLS10, versionTLS12, true, true, jarmForward, false, false, "1.3", jarmForward},
+	{"tls1_3_middle_out", versionTLS10, versionTLS12, true, false, jarmMiddleOut, true, false, "1.3", jarmReverse},
+}
+
+// JARMProbeResult menyimpan respons server untuk satu probe JARM
+type JARMProbeResult struct {
+	Name  string `json:"name"`
+	Raw   string `json:"raw"`
+	Error string `json:"error,omitempty"`
+}
+
+// JARMResult menyimpan fingerprint JARM sebuah server TLS
+type JARMResult struct {
+	Target      string            `json:"target"`
+	Address     string            `json:"address"`
+	Fingerprint string            `json:"fingerprint"`
+	Raw         string            `json:"raw"`
+	Probes      []JARMProbeResult `json:"probes,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Duration    string            `json:"duration"`
+}
+
+// JARMCluster mengelompokkan address dengan fingerprint JARM yang sama
+type JARMCluster struct {
+	Fingerprint string   `json:"fingerprint"`
+	Addresses   []string `json:"addresses"`
+}
+
+// JARMReport menyimpan hasil JARM beberapa server beserta cluster fingerprint-nya
+type JARMReport struct {
+	Results  []*JARMResult `json:"results"`
+	Clusters []JARMCluster `json:"clusters"`
+}
+
+// JARM menghitung fingerprint JARM untuk target host atau host:port (default 443)
+func (f *FingerprintSpoofer) JARM(target string) *JARMResult {
+	host, port := f.parseTarget(target)
+	if port == "" {
+		port = "443"
+	}
+
+	result := f.JARMAddress(net.JoinHostPort(host, port), host)
+	result.Target = target
+	return result
+}
+
+// JARMAddress mengirim 10 ClientHello JARM ke address (IP:port) dengan SNI host
+// lalu meng-hash cipher, versi, ALPN dan extension dari setiap ServerHello.
+// Hasilnya kompatibel dengan implementasi referensi JARM
+func (f *FingerprintSpoofer) JARMAddress(address, host string) *JARMResult {
+	start := time.Now()
+
+	result := &JARMResult{
+		Target:    host,
+		Address:   address,
+		Timestamp: start,
+	}
+
+	var raws []string
+	failures := 0
+	for _, probe := range jarmProbes {
+		probeResult := JARMProbeResult{Name: probe.Name, Raw: jarmEmptyProbe}
+
+		response, err := exchangeClientHello(address, buildClientHello(probe.spec(host)), tlsEnumTimeout)
+		if err != nil {
+			probeResult.Error = err.Error()
+			failures++
+		} else if response.Hello != nil {
+			probeResult.Raw = jarmServerHello(response.Hello)
+		}
+
+		result.Probes = append(result.Probes, probeResult)
+		raws = append(raws, probeResult.Raw)
+	}
+
+	if failures == len(jarmProbes) {
+		result.Error = result.Probes[len(result.Probes)-1].Error
+	}
+
+	result.Raw = strings.Join(raws, ",")
+	result.Fingerprint = jarmHash(raws)
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+
+	f.logger.Debug(fmt.Sprintf("JARM %s: %s", address, result.Fingerprint))
+	return result
+}
+
+// BuildJARMReport mengelompokkan hasil berdasarkan fingerprint JARM, cluster terbesar lebih dulu.
+// Hasil tanpa respons TLS (fingerprint nol) tidak dikelompokkan
+func BuildJARMReport(results []*JARMResult) *JARMReport {
+	groups := make(map[string][]string)
+	for _, result := range results {
+		if result.Fingerprint == "" || result.Fingerprint == strings.Repeat("0", 62) {
+			continue
+		}
+		groups[result.Fingerprint] = append(groups[result.Fingerprint], result.Address)
+	}
+
+	clusters := make([]JARMCluster, 0, len(groups))
+	for fingerprint, addresses := range groups {
+		sort.Strings(addresses)
+		clusters = append(clusters, JARMCluster{Fingerprint: fingerprint, Addresses: addresses})
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Addresses) != len(clusters[j].Addresses) {
+			return len(clusters[i].Addresses) > len(clusters[j].Addresses)
+		}
+		return clusters[i].Fingerprint < clusters[j].Fingerprint
+	})
+
+	return &JARMReport{Results: results, Clusters: clusters}
+}
+
+// spec menyusun clientHelloSpec untuk probe dengan blok extension JARM
+func (p jarmProbe) spec(host string) *clientHelloSpec {
+	ciphers := make([]uint16, 0, len(jarmCiphers)+1)
+	for _, cipher := range jarmCiphers {
+		if p.NoTLS13Ciphers && cipher>>8 == 0x13 {
+			continue
+		}
+		ciphers = append(ciphers, cipher)
+	}
+
+	ordered := make([]uint16, 0, len(ciphers)+1)
+	if p.GREASE {
+		ordered = append(ordered, randomGREASE())
+	}
+	for _, i := range jarmOrder(len(ciphers), p.CipherOrder) {
+		ordered = append(ordered, ciphers[i])
+	}
+
+	return &clientHelloSpec{
+		Version:       p.HelloVersion,
+		RecordVersion: p.RecordVersion,
+		CipherSuites:  ordered,
+		Extensions:    p.extensions(host),
+	}
+}
+
+// extensions menyusun blok extension dengan urutan yang sama seperti JARM
+func (p jarmProbe) extensions(host string) []byte {
+	var extensions []byte
+
+	if p.GREASE {
+		extensions = appendExtension(extensions, randomGREASE(), nil)
+	}
+
+	name := []byte(host)
+	var serverName []byte
+	serverName = appendUint16(serverName, uint16(len(name)+3))
+	serverName = append(serverName, 0) // host_name
+	serverName = appendUint16(serverName, uint16(len(name)))
+	serverName = append(serverName, name...)
+	extensions = appendExtension(extensions, tlsExtServerName, serverName)
+
+	extensions = appendExtension(extensions, tlsExtExtendedMasterSec, nil)
+	extensions = appendExtension(extensions, tlsExtMaxFragmentLength, []byte{1})
+	extensions = appendExtension(extensions, tlsExtRenegotiationInfo, []byte{0})
+	extensions = appendExtension(extensions, tlsExtSupportedGroups,
+		[]byte{0x00, 0x08, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18, 0x00, 0x19})
+	extensions = appendExtension(extensions, tlsExtPointFormats, []byte{1, 0})
+	extensions = appendExtension(extensions, tlsExtSessionTicket, nil)
+
+	alpns := jarmALPN
+	if p.RareALPN {
+		alpns = jarmRareALPN
+	}
+	var protocols []byte
+	for _, i := range jarmOrder(len(alpns), p.ExtensionOrder) {
+		protocols = append(protocols, byte(len(alpns[i])))
+		protocols = append(protocols, alpns[i]...)
+	}
+	extensions = appendExtension(extensions, tlsExtALPN, append(appendUint16(nil, uint16(len(protocols))), protocols...))
+
+	extensions = appendExtension(extensions, tlsExtSignatureAlgs, []byte{
+		0x00, 0x12, 0x04, 0x03, 0x08, 0x04, 0x04, 0x01, 0x05, 0x03,
+		0x08, 0x05, 0x05, 0x01, 0x08, 0x06, 0x06, 0x01, 0x02, 0x01,
+	})
+
+	// Key share X25519 (diawali GREASE jika aktif); public key random cukup
+	var shares []byte
+	if p.GREASE {
+		shares = appendUint16(shares, randomGREASE())
+		shares = append(shares, 0x00, 0x01, 0x00)
+	}
+	key := make([]byte, 32)
+	rand.Read(key)
+	shares = appendUint16(shares, tlsGroupX25519)
+	shares = appendUint16(shares, uint16(len(key)))
+	shares = append(shares, key...)
+	extensions = appendExtension(extensions, tlsExtKeyShare, append(appendUint16(nil, uint16(len(shares))), shares...))
+
+	extensions = appendExtension(extensions, tlsExtPSKKeyExchangeMode, []byte{1, 1})
+
+	if p.TLS13 || p.SupportedVersions == "1.2" {
+		versions := []uint16{versionTLS10, versionTLS11, versionTLS12}
+		if p.SupportedVersions != "1.2" {
+			versions = append(versions, versionTLS13)
+		}
+
+		var list []byte
+		if p.GREASE {
+			list = appendUint16(list, randomGREASE())
+		}
+		for _, i := range jarmOrder(len(versions), p.ExtensionOrder) {
+			list = appendUint16(list, versions[i])
+		}
+		extensions = appendExtension(extensions, tlsExtSupportedVersions, append([]byte{byte(len(list))}, list...))
+	}
+
+	return extensions
+}
+
+// jarmOrder mengembalikan urutan index untuk n elemen sesuai mode JARM
+func jarmOrder(n int, order string) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	return mungeIndices(indices, order)
+}
+
+// mungeIndices mengurutkan ulang index seperti cipher_mung pada JARM
+func mungeIndices(indices []int, order string) []int {
+	n := len(indices)
+	var output []int
+
+	switch order {
+	case jarmReverse:
+		for i := n - 1; i >= 0; i-- {
+			output = append(output, indices[i])
+		}
+	case jarmBottomHalf:
+		if n%2 == 1 {
+			output = append(output, indices[n/2+1:]...)
+		} else {
+			output = append(output, indices[n/2:]...)
+		}
+	case jarmTopHalf:
+		// Elemen tengah ikut top half jika jumlahnya ganjil
+		if n%2 == 1 {
+			output = append(output, indices[n/2])
+		}
+		output = append(output, mungeIndices(mungeIndices(indices, jarmReverse), jarmBottomHalf)...)
+	case jarmMiddleOut:
+		middle := n / 2
+		if n%2 == 1 {
+			output = append(output, indices[middle])
+			for i := 1; i <= middle; i++ {
+				output = append(output, indices[middle+i], indices[middle-i])
+			}
+		} else {
+			for i := 1; i <= middle; i++ {
+				output = append(output, indices[middle-1+i], indices[middle-i])
+			}
+		}
+	default:
+		output = append(output, indices...)
+	}
+
+	return output
+}
+
+// jarmServerHello memformat ServerHello menjadi "cipher|versi|alpn|extensions"
+func jarmServerHello(hello *helloMessage) string {
+	var cipher uint16
+	if len(hello.CipherSuites) > 0 {
+		cipher = hello.CipherSuites[0]
+	}
+
+	alpn := ""
+	if data := hello.ExtData[tlsExtALPN]; len(data) > 3 {
+		alpn = string(data[3:])
+	}
+
+	types := make([]string, 0, len(hello.Extensions))
+	for _, extType := range hello.Extensions {
+		types = append(types, fmt.Sprintf("%04x", extType))
+	}
+
+	return fmt.Sprintf("%04x|%04x|%s|%s", cipher, hello.Version, alpn, strings.Join(types, "-"))
+}
+
+// jarmHash menghasilkan fingerprint 62 karakter: 30 karakter cipher+versi per probe
+// ditambah 32 karakter pertama SHA-256 dari ALPN dan extension
+func jarmHash(raws []string) string {
+	empty := true
+	for _, raw := range raws {
+		if raw != jarmEmptyProbe {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return strings.Repeat("0", 62)
+	}
+
+	var fuzzy, alpnsAndExtensions strings.Builder
+	for _, raw := range raws {
+		components := strings.Split(raw, "|")
+		fuzzy.WriteString(jarmCipherByte(components[0]))
+		fuzzy.WriteString(jarmVersionByte(components[1]))
+		alpnsAndExtensions.WriteString(components[2])
+		alpnsAndExtensions.WriteString(components[3])
+	}
+
+	sum := sha256.Sum256([]byte(alpnsAndExtensions.String()))
+	return fuzzy.String() + hex.EncodeToString(sum[:])[:32]
+}
+
+// jarmCipherByte memetakan cipher ke posisi (1-based, hex 2 digit) di jarmCipherIndex
+func jarmCipherByte(cipher string) string {
+	if cipher == "" {
+		return "00"
+	}
+
+	position := len(jarmCipherIndex) + 1
+	for i, candidate := range jarmCipherIndex {
+		if fmt.Sprintf("%04x", candidate) == cipher {
+			position = i + 1
+			break
+		}
+	}
+	return fmt.Sprintf("%02x", position)
+}
+
+// jarmVersionByte memetakan versi "03xx" ke satu huruf (0300=a ... 0305=f)
+func jarmVersionByte(version string) string {
+	if len(version) < 4 {
+		return "0"
+	}
+	minor := int(version[3] - '0')
+	if minor < 0 || minor > 5 {
+		return "0"
+	}
+	return string("abcdef"[minor])
+}
+
+// randomGREASE memilih nilai GREASE (RFC 8701) secara acak
+func randomGREASE() uint16 {
+	n, err := rand.Int(rand.Reader, big.NewInt(16))
+	if err != nil {
+		return 0x0a0a
+	}
+	return uint16(n.Int64())<<12 | 0x0a0a
+}
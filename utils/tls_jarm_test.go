@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMungeIndices(t *testing.T) {
+	// Nilai diharapkan dihitung dengan cipher_mung dari implementasi referensi JARM
+	tests := []struct {
+		n     int
+		order string
+		want  []int
+	}{
+		{5, jarmForward, []int{0, 1, 2, 3, 4}},
+		{0, jarmReverse, []int{}},
+		{1, jarmReverse, []int{0}},
+		{5, jarmReverse, []int{4, 3, 2, 1, 0}},
+		{6, jarmReverse, []int{5, 4, 3, 2, 1, 0}},
+		{1, jarmTopHalf, []int{0}},
+		{2, jarmTopHalf, []int{0}},
+		{4, jarmTopHalf, []int{1, 0}},
+		{5, jarmTopHalf, []int{2, 1, 0}},
+		{6, jarmTopHalf, []int{2, 1, 0}},
+		{1, jarmBottomHalf, []int{}},
+		{2, jarmBottomHalf, []int{1}},
+		{5, jarmBottomHalf, []int{3, 4}},
+		{6, jarmBottomHalf, []int{3, 4, 5}},
+		{1, jarmMiddleOut, []int{0}},
+		{2, jarmMiddleOut, []int{1, 0}},
+		{4, jarmMiddleOut, []int{2, 1, 3, 0}},
+		{5, jarmMiddleOut, []int{2, 3, 1, 4, 0}},
+		{6, jarmMiddleOut, []int{3, 2, 4, 1, 5, 0}},
+	}
+
+	for _, tt := range tests {
+		got := jarmOrder(tt.n, tt.order)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("jarmOrder(%d, %s) = %v, want %v", tt.n, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestMungeIndicesCipherList(t *testing.T) {
+	n := len(jarmCiphers)
+
+	tests := []struct {
+		order string
+		count int
+		first int
+		last  int
+	}{
+		{jarmForward, n, 0, n - 1},
+		{jarmReverse, n, n - 1, 0},
+		{jarmTopHalf, n/2 + 1, n / 2, 0},
+		{jarmBottomHalf, n / 2, n/2 + 1, n - 1},
+		{jarmMiddleOut, n, n / 2, 0},
+	}
+
+	for _, tt := range tests {
+		got := jarmOrder(n, tt.order)
+		if len(got) != tt.count || got[0] != tt.first || got[len(got)-1] != tt.last {
+			t.Errorf("jarmOrder(%d, %s): len=%d first=%d last=%d, want len=%d first=%d last=%d",
+				n, tt.order, len(got), got[0], got[len(got)-1], tt.count, tt.first, tt.last)
+		}
+	}
+}
+
+func TestJARMCipherAndVersionByte(t *testing.T) {
+	ciphers := []struct {
+		cipher string
+		want   string
+	}{
+		{"", "00"},
+		{"0004", "01"},
+		{"c02b", "27"},
+		{"c02f", "29"},
+		{"cca9", "40"},
+		{"1301", "41"},
+		{"1305", "45"},
+		{"0000", "46"},
+	}
+	for _, tt := range ciphers {
+		if got := jarmCipherByte(tt.cipher); got != tt.want {
+			t.Errorf("jarmCipherByte(%q) = %s, want %s", tt.cipher, got, tt.want)
+		}
+	}
+
+	versions := []struct {
+		version string
+		want    string
+	}{
+		{"", "0"},
+		{"0300", "a"},
+		{"0301", "b"},
+		{"0302", "c"},
+		{"0303", "d"},
+		{"0304", "e"},
+		{"0305", "f"},
+		{"0309", "0"},
+	}
+	for _, tt := range versions {
+		if got := jarmVersionByte(tt.version); got != tt.want {
+			t.Errorf("jarmVersionByte(%q) = %s, want %s", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestJARMHash(t *testing.T) {
+	empty := make([]string, len(jarmProbes))
+	for i := range empty {
+		empty[i] = jarmEmptyProbe
+	}
+
+	// Hash diharapkan dihitung dengan jarm_hash dari implementasi referensi JARM
+	tests := []struct {
+		name string
+		raws []string
+		want string
+	}{
+		{"semua probe kosong", empty, strings.Repeat("0", 62)},
+		{
+			"server TLS 1.3",
+			[]string{
+				"c02b|0303|h2|ff01-0000-0001-000b-0023-0010-0017",
+				"cca9|0303|h2|ff01-0000-0001-000b-0023-0010-0017",
+				"cca9|0303||ff01-0000-0001-000b-0023-0017",
+				"c02f|0303||ff01-0000-0001-000b-0023-0017",
+				"cca9|0303|h2|ff01-0000-0001-000b-0023-0010-0017",
+				"c009|0302||ff01-0000-0001-000b-0023-0017",
+				"1302|0303|h2|002b-0033",
+				"1303|0303|h2|002b-0033",
+				"|||",
+				"1301|0303|h2|002b-0033",
+			},
+			"27d40d40d29d40d1dc42d43d00041d38851192c1122f2a1117606567490e6f",
+		},
+		{
+			"sebagian probe kosong dan cipher tidak dikenal",
+			[]string{"0035|0301||ff01", "|||", "|||", "|||", "|||", "|||", "|||", "|||", "|||", "0000|0300||"},
+			"08b00000000000000000000000046abc98f8e001b5ed0d7b91de1cdd769719",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := jarmHash(tt.raws); got != tt.want {
+			t.Errorf("%s: jarmHash = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
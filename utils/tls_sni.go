@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mode probe SNI
+const (
+	SNIModeCorrect = "correct"
+	SNIModeNone    = "none"
+	SNIModeBogus   = "bogus"
+)
+
+// SNIProbe menyimpan sertifikat leaf yang dikembalikan untuk satu nilai SNI
+type SNIProbe struct {
+	Mode        string   `json:"mode"`
+	ServerName  string   `json:"server_name,omitempty"`
+	Subject     string   `json:"subject,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	DNSNames    []string `json:"dns_names,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// SNIComparison membandingkan sertifikat dengan SNI benar, tanpa SNI dan SNI palsu
+type SNIComparison struct {
+	Address string     `json:"address"`
+	Host    string     `json:"host"`
+	Probes  []SNIProbe `json:"probes"`
+
+	// SNIRequired: sertifikat tanpa SNI berbeda dari sertifikat host (vhost berbasis SNI)
+	SNIRequired bool `json:"sni_required"`
+	// CatchAll: SNI palsu tetap mendapat sertifikat host (host adalah default vhost)
+	CatchAll bool `json:"catch_all"`
+	// DefaultVhost: subject sertifikat default jika bukan milik host
+	DefaultVhost string `json:"default_vhost,omitempty"`
+	// HiddenNames: SAN di sertifikat default/palsu yang tidak ada di sertifikat host
+	HiddenNames []string `json:"hidden_names,omitempty"`
+	// Candidates: semua SAN non-wildcard yang bisa dijadikan target baru
+	Candidates []string `json:"candidates,omitempty"`
+}
+
+// CompareSNI melakukan handshake ke address dengan SNI host, tanpa SNI, dan dengan SNI
+// palsu lalu membandingkan sertifikatnya untuk mendeteksi default vhost dan site tersembunyi
+func (f *FingerprintSpoofer) CompareSNI(address, host string, timeout time.Duration) *SNIComparison {
+	comparison := &SNIComparison{
+		Address: address,
+		Host:    host,
+	}
+
+	correct := probeSNI(address, host, SNIModeCorrect, timeout)
+	if net.ParseIP(host) != nil {
+		// Target IP tidak punya SNI, probe "correct" sama dengan tanpa SNI
+		correct.ServerName = ""
+	}
+	none := probeSNI(address, "", SNIModeNone, timeout)
+	bogus := probeSNI(address, randomBogusSNI(), SNIModeBogus, timeout)
+	comparison.Probes = []SNIProbe{*correct, *none, *bogus}
+
+	if correct.Fingerprint != "" && none.Fingerprint != "" && correct.Fingerprint != none.Fingerprint {
+		comparison.SNIRequired = true
+		comparison.DefaultVhost = none.Subject
+	}
+	if correct.Fingerprint != "" && bogus.Fingerprint == correct.Fingerprint {
+		comparison.CatchAll = true
+	}
+
+	hostNames := make(map[string]bool)
+	for _, name := range correct.DNSNames {
+		hostNames[strings.ToLower(name)] = true
+	}
+
+	hidden := make(map[string]bool)
+	candidates := make(map[string]bool)
+	for _, probe := range comparison.Probes {
+		for _, name := range probe.DNSNames {
+			name = strings.ToLower(strings.TrimSuffix(name, "."))
+			if probe.Mode != SNIModeCorrect && !hostNames[name] {
+				hidden[name] = true
+			}
+			if !strings.HasPrefix(name, "*.") && name != strings.ToLower(host) && ValidateDomain(name) {
+				candidates[name] = true
+			}
+		}
+	}
+
+	comparison.HiddenNames = sortedKeys(hidden)
+	comparison.Candidates = sortedKeys(candidates)
+
+	f.logger.Debug(fmt.Sprintf("SNI comparison %s: %d kandidat SAN", address, len(comparison.Candidates)))
+	return comparison
+}
+
+// probeSNI melakukan handshake dengan serverName tertentu ("" berarti tanpa SNI)
+func probeSNI(address, serverName, mode string, timeout time.Duration) *SNIProbe {
+	probe := &SNIProbe{Mode: mode, ServerName: serverName}
+
+	rawConn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+	defer rawConn.Close()
+
+	conn := tls.Client(rawConn, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true, // Yang dibandingkan hanya identitas sertifikat
+	})
+	conn.SetDeadline(time.Now().Add(timeout))
+	if err := conn.Handshake(); err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return probe
+	}
+
+	cert := describeCertificate(state.PeerCertificates[0])
+	probe.Subject = cert.Subject
+	probe.Fingerprint = cert.Fingerprint
+	probe.DNSNames = cert.DNSNames
+	return probe
+}
+
+// randomBogusSNI membuat hostname acak di TLD .invalid (RFC 2606)
+func randomBogusSNI() string {
+	label := make([]byte, 6)
+	rand.Read(label)
+	return "veko-" + hex.EncodeToString(label) + ".invalid"
+}
+
+// sortedKeys mengembalikan key map yang diurutkan
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
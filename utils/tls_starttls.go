@@ -0,0 +1,492 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// starttlsTimeout adalah timeout negosiasi STARTTLS dan handshake TLS
+const starttlsTimeout = 10 * time.Second
+
+// maxSTARTTLSResponse membatasi ukuran respons plaintext yang dibaca sebelum upgrade
+const maxSTARTTLSResponse = 64 * 1024
+
+// STARTTLSPorts memetakan port plaintext ke protokol STARTTLS-nya
+var STARTTLSPorts = map[int]string{
+	21:   "ftp",
+	25:   "smtp",
+	110:  "pop3",
+	143:  "imap",
+	389:  "ldap",
+	587:  "smtp",
+	5222: "xmpp",
+	5432: "postgres",
+}
+
+// errSTARTTLSNotOffered menandakan server tidak menawarkan STARTTLS
+var errSTARTTLSNotOffered = errors.New("STARTTLS tidak ditawarkan")
+
+// STARTTLSResult menyimpan hasil upgrade STARTTLS dan analisis TLS-nya
+type STARTTLSResult struct {
+	Protocol    string          `json:"protocol"`
+	Address     string          `json:"address"`
+	Offered     bool            `json:"offered"`
+	Fingerprint *TLSFingerprint `json:"fingerprint,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// AnalyzeSTARTTLS membuka koneksi plaintext, melakukan upgrade STARTTLS sesuai
+// protokol lalu menjalankan analisis sertifikat dan fingerprint yang sama dengan AnalyzeTLS
+func (f *FingerprintSpoofer) AnalyzeSTARTTLS(address, serverName, protocol string) *STARTTLSResult {
+	result := &STARTTLSResult{
+		Protocol: protocol,
+		Address:  address,
+	}
+
+	conn, err := net.DialTimeout("tcp", address, starttlsTimeout)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(starttlsTimeout))
+	if err := negotiateSTARTTLS(conn, protocol, serverName); err != nil {
+		if err != errSTARTTLSNotOffered {
+			result.Error = err.Error()
+		}
+		f.logger.Debug(fmt.Sprintf("STARTTLS %s gagal untuk %s: %v", protocol, address, err))
+		return result
+	}
+	result.Offered = true
+
+	fingerprint, _, err := f.handshakeConn(conn, f.createPermissiveTLSConfig(serverName), serverName, starttlsTimeout)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Fingerprint = fingerprint
+
+	return result
+}
+
+// negotiateSTARTTLS menjalankan dialog plaintext sampai koneksi siap untuk TLS handshake
+func negotiateSTARTTLS(conn net.Conn, protocol, serverName string) error {
+	// Server diam setelah respons STARTTLS sampai ClientHello diterima,
+	// jadi buffer reader tidak menelan byte handshake
+	reader := bufio.NewReader(conn)
+
+	switch protocol {
+	case "smtp":
+		return starttlsSMTP(conn, reader)
+	case "imap":
+		return starttlsIMAP(conn, reader)
+	case "pop3":
+		return starttlsPOP3(conn, reader)
+	case "ftp":
+		return starttlsFTP(conn, reader)
+	case "xmpp":
+		return starttlsXMPP(conn, reader, serverName)
+	case "ldap":
+		return starttlsLDAP(conn)
+	case "postgres":
+		return starttlsPostgres(conn)
+	default:
+		return fmt.Errorf("protokol STARTTLS tidak dikenal: %s", protocol)
+	}
+}
+
+// starttlsSMTP: EHLO lalu STARTTLS (RFC 3207)
+func starttlsSMTP(conn net.Conn, reader *bufio.Reader) error {
+	if _, err := expectReply(reader, "220"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(conn, "EHLO veko-grid.local\r\n"); err != nil {
+		return err
+	}
+	lines, err := expectReply(reader, "250")
+	if err != nil {
+		return err
+	}
+	if !containsLine(lines, "STARTTLS") {
+		return errSTARTTLSNotOffered
+	}
+
+	if _, err := fmt.Fprintf(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	_, err = expectReply(reader, "220")
+	return err
+}
+
+// starttlsIMAP: CAPABILITY lalu STARTTLS (RFC 3501)
+func starttlsIMAP(conn net.Conn, reader *bufio.Reader) error {
+	greeting, err := readLine(reader)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("greeting IMAP tidak valid: %s", greeting)
+	}
+
+	if _, err := fmt.Fprintf(conn, "a001 CAPABILITY\r\n"); err != nil {
+		return err
+	}
+	lines, err := readTagged(reader, "a001")
+	if err != nil {
+		return err
+	}
+	if !containsLine(lines, "STARTTLS") {
+		return errSTARTTLSNotOffered
+	}
+
+	if _, err := fmt.Fprintf(conn, "a002 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	lines, err = readTagged(reader, "a002")
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(lines[len(lines)-1], "a002 OK") {
+		return fmt.Errorf("STARTTLS ditolak: %s", lines[len(lines)-1])
+	}
+	return nil
+}
+
+// starttlsPOP3: CAPA lalu STLS (RFC 2595)
+func starttlsPOP3(conn net.Conn, reader *bufio.Reader) error {
+	greeting, err := readLine(reader)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(greeting, "+OK") {
+		return fmt.Errorf("greeting POP3 tidak valid: %s", greeting)
+	}
+
+	if _, err := fmt.Fprintf(conn, "CAPA\r\n"); err != nil {
+		return err
+	}
+	status, err := readLine(reader)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(status, "+OK") {
+		return errSTARTTLSNotOffered
+	}
+
+	offered := false
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+		if line == "." {
+			break
+		}
+		if strings.EqualFold(strings.TrimSpace(line), "STLS") {
+			offered = true
+		}
+	}
+	if !offered {
+		return errSTARTTLSNotOffered
+	}
+
+	if _, err := fmt.Fprintf(conn, "STLS\r\n"); err != nil {
+		return err
+	}
+	status, err = readLine(reader)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(status, "+OK") {
+		return fmt.Errorf("STLS ditolak: %s", status)
+	}
+	return nil
+}
+
+// starttlsFTP: FEAT lalu AUTH TLS (RFC 4217)
+func starttlsFTP(conn net.Conn, reader *bufio.Reader) error {
+	if _, err := expectReply(reader, "220"); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(conn, "FEAT\r\n"); err != nil {
+		return err
+	}
+	lines, _, err := readReply(reader)
+	if err != nil {
+		return err
+	}
+
+	if !containsLine(lines, "AUTH TLS") {
+		// Beberapa server tidak mengiklankan AUTH di FEAT, tetap dicoba
+		if _, err := fmt.Fprintf(conn, "AUTH TLS\r\n"); err != nil {
+			return err
+		}
+		if _, code, err := readReply(reader); err != nil || code != "234" {
+			return errSTARTTLSNotOffered
+		}
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(conn, "AUTH TLS\r\n"); err != nil {
+		return err
+	}
+	_, err = expectReply(reader, "234")
+	return err
+}
+
+// starttlsXMPP: stream header lalu <starttls/> (RFC 6120)
+func starttlsXMPP(conn net.Conn, reader *bufio.Reader, serverName string) error {
+	header := fmt.Sprintf("<?xml version='1.0'?><stream:stream to='%s' xmlns='jabber:client' "+
+		"xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>", serverName)
+	if _, err := conn.Write([]byte(header)); err != nil {
+		return err
+	}
+
+	features, err := readUntil(reader, "</stream:features>")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(features, "<starttls") {
+		return errSTARTTLSNotOffered
+	}
+
+	if _, err := conn.Write([]byte("<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>")); err != nil {
+		return err
+	}
+
+	response, err := readUntil(reader, ">")
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(response, "<proceed") {
+		return fmt.Errorf("STARTTLS ditolak: %s", response)
+	}
+	return nil
+}
+
+// starttlsLDAP: ExtendedRequest StartTLS OID 1.3.6.1.4.1.1466.20037 (RFC 4511)
+func starttlsLDAP(conn net.Conn) error {
+	oid := "1.3.6.1.4.1.1466.20037"
+
+	request := []byte{0x80, byte(len(oid))}
+	request = append(request, oid...)
+	request = append([]byte{0x77, byte(len(request))}, request...)
+	request = append([]byte{0x02, 0x01, 0x01}, request...) // messageID 1
+	request = append([]byte{0x30, byte(len(request))}, request...)
+
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	message, err := readBERElement(conn)
+	if err != nil {
+		return err
+	}
+
+	// LDAPMessage: messageID INTEGER, lalu ExtendedResponse [APPLICATION 24]
+	r := &byteReader{data: message}
+	if r.uint8() != 0x02 {
+		return fmt.Errorf("messageID LDAP tidak valid")
+	}
+	r.skip(int(r.uint8()))
+	if r.uint8() != 0x78 {
+		return fmt.Errorf("respons LDAP bukan ExtendedResponse")
+	}
+	if _, err := readBERLength(r); err != nil {
+		return err
+	}
+
+	// resultCode ENUMERATED
+	if r.uint8() != 0x0a || r.uint8() != 0x01 {
+		return fmt.Errorf("resultCode LDAP tidak valid")
+	}
+	code := r.uint8()
+	if r.err != nil {
+		return r.err
+	}
+	if code != 0 {
+		return errSTARTTLSNotOffered
+	}
+	return nil
+}
+
+// starttlsPostgres: SSLRequest, server membalas 'S' atau 'N'
+func starttlsPostgres(conn net.Conn) error {
+	// Length 8, kode 80877103
+	if _, err := conn.Write([]byte{0, 0, 0, 8, 0x04, 0xd2, 0x16, 0x2f}); err != nil {
+		return err
+	}
+
+	response := make([]byte, 1)
+	if _, err := conn.Read(response); err != nil {
+		return err
+	}
+
+	switch response[0] {
+	case 'S':
+		return nil
+	case 'N':
+		return errSTARTTLSNotOffered
+	default:
+		return fmt.Errorf("respons SSLRequest tidak valid: 0x%02x", response[0])
+	}
+}
+
+// readLine membaca satu baris tanpa CRLF
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readReply membaca reply multi-line gaya SMTP/FTP ("250-..." sampai "250 ...")
+func readReply(reader *bufio.Reader) ([]string, string, error) {
+	var lines []string
+	var code string
+	read := 0
+
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return nil, "", err
+		}
+		read += len(line)
+		if read > maxSTARTTLSResponse {
+			return nil, "", fmt.Errorf("reply terlalu panjang")
+		}
+		lines = append(lines, line)
+
+		if code == "" {
+			if len(line) < 3 {
+				return nil, "", fmt.Errorf("reply tidak valid: %s", line)
+			}
+			code = line[:3]
+		}
+		if line == code || strings.HasPrefix(line, code+" ") {
+			return lines, code, nil
+		}
+	}
+}
+
+// expectReply membaca reply dan memastikan kodenya sesuai
+func expectReply(reader *bufio.Reader, code string) ([]string, error) {
+	lines, got, err := readReply(reader)
+	if err != nil {
+		return nil, err
+	}
+	if got != code {
+		return nil, fmt.Errorf("reply tidak terduga: %s", lines[len(lines)-1])
+	}
+	return lines, nil
+}
+
+// readTagged membaca respons IMAP sampai baris dengan tag yang diberikan
+func readTagged(reader *bufio.Reader, tag string) ([]string, error) {
+	var lines []string
+	for len(lines) < 1000 {
+		line, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+		if strings.HasPrefix(line, tag+" ") {
+			return lines, nil
+		}
+	}
+	return nil, fmt.Errorf("respons tag %s tidak ditemukan", tag)
+}
+
+// readUntil membaca stream sampai marker ditemukan
+func readUntil(reader *bufio.Reader, marker string) (string, error) {
+	var buf strings.Builder
+	for buf.Len() < maxSTARTTLSResponse {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		buf.WriteByte(b)
+		if strings.HasSuffix(buf.String(), marker) {
+			return buf.String(), nil
+		}
+	}
+	return "", fmt.Errorf("marker %s tidak ditemukan", marker)
+}
+
+// containsLine mengecek apakah keyword muncul di salah satu baris capability
+func containsLine(lines []string, keyword string) bool {
+	for _, line := range lines {
+		if strings.Contains(strings.ToUpper(line), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBERElement membaca satu elemen BER (tag, length, content) dan mengembalikan content
+func readBERElement(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := readFull(conn, header); err != nil {
+		return nil, err
+	}
+
+	length := int(header[1])
+	if header[1]&0x80 != 0 {
+		size := int(header[1] & 0x7f)
+		if size == 0 || size > 4 {
+			return nil, fmt.Errorf("panjang BER tidak valid")
+		}
+		lengthBytes := make([]byte, size)
+		if _, err := readFull(conn, lengthBytes); err != nil {
+			return nil, err
+		}
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxSTARTTLSResponse {
+		return nil, fmt.Errorf("elemen BER terlalu besar")
+	}
+
+	content := make([]byte, length)
+	if _, err := readFull(conn, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// readBERLength membaca panjang BER dari byteReader
+func readBERLength(r *byteReader) (int, error) {
+	first := r.uint8()
+	if first&0x80 == 0 {
+		return int(first), r.err
+	}
+
+	length := 0
+	for _, b := range r.bytes(int(first & 0x7f)) {
+		length = length<<8 | int(b)
+	}
+	return length, r.err
+}
+
+// readFull membaca tepat len(buf) byte dari koneksi
+func readFull(conn net.Conn, buf []byte) (int, error) {
+	read := 0
+	for read < len(buf) {
+		n, err := conn.Read(buf[read:])
+		read += n
+		if err != nil {
+			return read, err
+		}
+	}
+	return read, nil
+}